@@ -0,0 +1,227 @@
+package st
+
+import (
+	"cmp"
+)
+
+// Extends SpanBoundry[E] with the inclusivity of the Begin and End values.
+// SpanBoundry instances that do not implement this interface are treated as inclusive on both ends.
+//
+// Half open spans, [Begin, End), are the natural model for continuous types such as time.Time,
+// where a Next function can not meaningfully step to the next value.
+type BoundedSpanBoundry[E any] interface {
+	SpanBoundry[E]
+
+	// Returns true when the Begin value is part of the span.
+	BeginInclusive() bool
+
+	// Returns true when the End value is part of the span.
+	EndInclusive() bool
+}
+
+// Representation of a Span/Range of values, where each end can be open or closed.
+// The zero value of OpenBegin and OpenEnd represents a closed span: [Begin, End].
+type BoundedSpan[E any] struct {
+	// Start of the Span.
+	Begin E
+	// End of the Span.
+	End E
+	// When true, the Begin value is not part of the span.
+	OpenBegin bool
+	// When true, the End value is not part of the span.
+	OpenEnd bool
+}
+
+// Returns the Begin value
+func (s *BoundedSpan[E]) GetBegin() E {
+	return s.Begin
+}
+
+// Returns the End value
+func (s *BoundedSpan[E]) GetEnd() E {
+	return s.End
+}
+
+// Returns true when the Begin value is part of the span.
+func (s *BoundedSpan[E]) BeginInclusive() bool {
+	return !s.OpenBegin
+}
+
+// Returns true when the End value is part of the span.
+func (s *BoundedSpan[E]) EndInclusive() bool {
+	return !s.OpenEnd
+}
+
+// Creates a new SpanBoundry[E] with the given inclusivity of a and b, but does not do any error checking.
+func (s *SpanUtil[E]) Nb(a E, aInclusive bool, b E, bInclusive bool) SpanBoundry[E] {
+	return s.BoundedSpanFactory(a, aInclusive, b, bInclusive)
+}
+
+// Creates a new half open SpanBoundry[E]: [a, b), but does not do any error checking.
+func (s *SpanUtil[E]) HalfOpen(a, b E) SpanBoundry[E] {
+	return s.Nb(a, true, b, false)
+}
+
+// Used by wrappers such as OverlappingSpanSets, to expose the SpanBoundry they represent.
+type spanGetter[E any] interface {
+	GetSpan() SpanBoundry[E]
+}
+
+// Returns the inner most SpanBoundry represented by span.
+func leafSpan[E any](span SpanBoundry[E]) SpanBoundry[E] {
+	for {
+		var getter, ok = span.(spanGetter[E])
+		if !ok {
+			return span
+		}
+		var next = getter.GetSpan()
+		if next == nil || next == span {
+			return span
+		}
+		span = next
+	}
+}
+
+// Returns true when the Begin value of span is part of the span.
+func spanBeginInclusive[E any](span SpanBoundry[E]) bool {
+	if b, ok := leafSpan(span).(BoundedSpanBoundry[E]); ok {
+		return b.BeginInclusive()
+	}
+	return true
+}
+
+// Returns true when the End value of span is part of the span.
+func spanEndInclusive[E any](span SpanBoundry[E]) bool {
+	if b, ok := leafSpan(span).(BoundedSpanBoundry[E]); ok {
+		return b.EndInclusive()
+	}
+	return true
+}
+
+// Represents the begin or end of a span as a point on the line.
+type boundry[E any] struct {
+	value E
+
+	// Position relative to value: -1 just before, 0 at, 1 just after.
+	offset int
+
+	// True when the point came from a BoundedSpanBoundry.
+	bounded bool
+}
+
+// Returns the begin of span as a point.
+func (s *SpanUtil[E]) beginOf(span SpanBoundry[E]) boundry[E] {
+	// asserting a concrete type is much cheaper than asserting an interface, so the common types come first
+	switch v := span.(type) {
+	case *Span[E]:
+		return boundry[E]{value: v.Begin}
+	case *BoundedSpan[E]:
+		if v.OpenBegin {
+			return boundry[E]{value: v.Begin, offset: 1, bounded: true}
+		}
+		return boundry[E]{value: v.Begin, bounded: true}
+	case *OverlappingSpanSets[E]:
+		if v.Span != nil {
+			return s.beginOf(v.Span)
+		}
+	case *ColumnOverlapAccumulator[E]:
+		if v.Next != nil && v.Next.Span != nil {
+			return s.beginOf(v.Next.Span)
+		}
+	case *shardSpan[E]:
+		return v.begin
+	}
+	if b, ok := leafSpan(span).(BoundedSpanBoundry[E]); ok {
+		if b.BeginInclusive() {
+			return boundry[E]{value: b.GetBegin(), bounded: true}
+		}
+		return boundry[E]{value: b.GetBegin(), offset: 1, bounded: true}
+	}
+	return boundry[E]{value: span.GetBegin()}
+}
+
+// Returns the end of span as a point.
+func (s *SpanUtil[E]) endOf(span SpanBoundry[E]) boundry[E] {
+	switch v := span.(type) {
+	case *Span[E]:
+		return boundry[E]{value: v.End}
+	case *BoundedSpan[E]:
+		if v.OpenEnd {
+			return boundry[E]{value: v.End, offset: -1, bounded: true}
+		}
+		return boundry[E]{value: v.End, bounded: true}
+	case *OverlappingSpanSets[E]:
+		if v.Span != nil {
+			return s.endOf(v.Span)
+		}
+	case *ColumnOverlapAccumulator[E]:
+		if v.Next != nil && v.Next.Span != nil {
+			return s.endOf(v.Next.Span)
+		}
+	case *shardSpan[E]:
+		return v.end
+	}
+	if b, ok := leafSpan(span).(BoundedSpanBoundry[E]); ok {
+		if b.EndInclusive() {
+			return boundry[E]{value: b.GetEnd(), bounded: true}
+		}
+		return boundry[E]{value: b.GetEnd(), offset: -1, bounded: true}
+	}
+	return boundry[E]{value: span.GetEnd()}
+}
+
+// Compares the points a and b, returns -1,0,1.
+func (s *SpanUtil[E]) cmpBoundry(a, b boundry[E]) int {
+	var diff = s.Cmp(a.value, b.value)
+	if diff == 0 {
+		return cmp.Compare(a.offset, b.offset)
+	}
+	return diff
+}
+
+// Returns the first begin point that comes after the end point p.
+// The bool value is false when there is no point after p, see: SetInfinity and NextOk.
+func (s *SpanUtil[E]) after(p boundry[E]) (boundry[E], bool) {
+	if p.offset < 0 {
+		return boundry[E]{value: p.value, bounded: p.bounded}, true
+	}
+	if s.IsPosInf(p.value) {
+		return p, false
+	}
+	if s.hasNext() {
+		var next, ok = s.next(p.value)
+		if !ok {
+			return p, false
+		}
+		return boundry[E]{value: next, bounded: p.bounded}, true
+	}
+	return boundry[E]{value: p.value, offset: 1, bounded: true}, true
+}
+
+// Returns the last end point that comes before the begin point p.
+// The error is not nil when p is inclusive, Prev is nil and p did not come from a BoundedSpanBoundry,
+// or when there is no value before p.
+func (s *SpanUtil[E]) before(p boundry[E]) (boundry[E], error) {
+	if p.offset > 0 {
+		return boundry[E]{value: p.value, bounded: p.bounded}, nil
+	}
+	if s.hasPrev() {
+		var prev, ok = s.prev(p.value)
+		if !ok {
+			return p, ErrNoPrev
+		}
+		return boundry[E]{value: prev, bounded: p.bounded}, nil
+	}
+	if p.bounded {
+		return boundry[E]{value: p.value, offset: -1, bounded: true}, nil
+	}
+	return p, ErrPrevRequired
+}
+
+// Creates a new span from the begin point b and end point e.
+func (s *SpanUtil[E]) fromBoundries(b, e boundry[E]) SpanBoundry[E] {
+	if b.bounded || e.bounded || b.offset != 0 || e.offset != 0 {
+		return s.Nb(b.value, b.offset == 0, e.value, e.offset == 0)
+	}
+	return s.Ns(b.value, e.value)
+}
@@ -28,7 +28,23 @@ type ColumnOverlapAccumulator[E any] struct {
 	// Denotes if the object is closed
 	Closed bool
 
+	// When not nil, the data source encountered an error, see: SpanError.
 	Err error
+
+	// The name of the column, see: ColumnSets.AddNamedColumn.
+	Name string
+
+	// The metadata of the column, see: ColumnSets.AddNamedColumn.
+	Meta any
+
+	// Functions called by Close, before the iterator is stopped.
+	OnClose *[]func()
+
+	// When true, SetNext reuses the Overlaps list, see: ColumnSets.ReuseBuffers.
+	reuse bool
+
+	// When not nil, called with the error of a set, returns true when the set is dropped, see: ColumnSets.ErrorPolicy.
+	skip func(err error) bool
 }
 
 func (s *ColumnOverlapAccumulator[E]) GetBegin() E {
@@ -39,6 +55,24 @@ func (s *ColumnOverlapAccumulator[E]) GetEnd() E {
 	return s.Next.GetEnd()
 }
 
+// Returns true when the begin value of the next set is part of the span.
+func (s *ColumnOverlapAccumulator[E]) BeginInclusive() bool {
+	return spanBeginInclusive[E](s.Next)
+}
+
+// Returns true when the end value of the next set is part of the span.
+func (s *ColumnOverlapAccumulator[E]) EndInclusive() bool {
+	return spanEndInclusive[E](s.Next)
+}
+
+// Returns the span of the next set, or nil when there is no next set.
+func (s *ColumnOverlapAccumulator[E]) GetSpan() SpanBoundry[E] {
+	if s.Next == nil {
+		return nil
+	}
+	return s.Next.Span
+}
+
 // Returns the first span that intersecs with this set.
 func (s *ColumnOverlapAccumulator[E]) GetFirstSpan() (int, SpanBoundry[E]) {
 	return (*s.Overlaps)[0].GetFirstSpan()
@@ -87,9 +121,23 @@ func (s *ColumnOverlapAccumulator[E]) Close() {
 	if s.Closed {
 		return
 	}
+	s.Closed = true
+	if s.OnClose != nil {
+		for _, todo := range *s.OnClose {
+			todo()
+		}
+	}
 	if s.ItrStop != nil {
 		s.ItrStop()
-		s.Closed = true
+	}
+}
+
+// Adds a function to call when the close operation is called.
+func (s *ColumnOverlapAccumulator[E]) AddOnClose(todo func()) {
+	if s.OnClose == nil {
+		s.OnClose = &[]func(){todo}
+	} else {
+		*s.OnClose = append(*s.OnClose, todo)
 	}
 }
 
@@ -106,10 +154,11 @@ func (s *ColumnOverlapAccumulator[E]) SetNext(overlap SpanBoundry[E]) {
 
 	var current = s.Next
 	var hasnext = current != nil
-	var u = *s.Util
+	var u = s.Util
 	if hasnext && s.Overlaps != nil && len(*s.Overlaps) != 1 && u.Overlap(overlap, current) {
-		var ol = &[]*OverlappingSpanSets[E]{}
-		for _, span := range *s.Overlaps {
+		var list = *s.Overlaps
+		var ol = s.emptyOverlaps()
+		for _, span := range list {
 			if span == current {
 				break
 			}
@@ -117,18 +166,17 @@ func (s *ColumnOverlapAccumulator[E]) SetNext(overlap SpanBoundry[E]) {
 				*ol = append(*ol, span)
 			}
 		}
+		s.Overlaps = ol
 		if len(*ol) != 0 {
 			s.SrcStart = (*ol)[0].SrcBegin
-			s.Overlaps = ol
 		} else {
 			s.SrcStart = -1
 			s.SrcEnd = -1
-			s.Overlaps = &[]*OverlappingSpanSets[E]{}
 		}
 	} else {
 		s.SrcStart = -1
 		s.SrcEnd = -1
-		s.Overlaps = &[]*OverlappingSpanSets[E]{}
+		s.Overlaps = s.emptyOverlaps()
 	}
 
 	for hasnext {
@@ -146,14 +194,15 @@ func (s *ColumnOverlapAccumulator[E]) SetNext(overlap SpanBoundry[E]) {
 			}
 			s.SrcEnd = current.SrcEnd
 			*s.Overlaps = append(*s.Overlaps, current)
-			if u.Cmp(current.GetEnd(), overlap.GetEnd()) > 0 {
+			// current goes on when it contains the first point after overlap, so both ways of writing an end compare the same
+			if next, ok := u.after(u.endOf(overlap)); ok && u.cmpBoundry(u.endOf(current), next) > -1 {
 				return
 			}
-		} else if u.Cmp(current.GetBegin(), overlap.GetEnd()) > 0 {
+		} else if u.cmpBoundry(u.beginOf(current), u.endOf(overlap)) > 0 {
 			// current is after next, then we are done!
 			return
 		}
-		_, current, hasnext = s.ItrGetNext()
+		current, hasnext = s.pull()
 
 		if !hasnext {
 			if s.SrcStart == -1 {
@@ -163,3 +212,34 @@ func (s *ColumnOverlapAccumulator[E]) SetNext(overlap SpanBoundry[E]) {
 		}
 	}
 }
+
+// Returns the next set from the iterator, sets with an error that skip drops are never returned.
+func (s *ColumnOverlapAccumulator[E]) pull() (*OverlappingSpanSets[E], bool) {
+	for {
+		var _, current, ok = s.ItrGetNext()
+		if !ok || current.Err == nil || s.skip == nil || !s.skip(current.Err) {
+			return current, ok
+		}
+	}
+}
+
+// Drops Next when it has an error that skip drops, used for the set read when the instance was created.
+func (s *ColumnOverlapAccumulator[E]) skipNext() {
+	if s.Closed || s.Next == nil || s.Next.Err == nil || s.skip == nil || !s.skip(s.Next.Err) {
+		return
+	}
+	var current, ok = s.pull()
+	s.Next, s.Err = nil, nil
+	if ok {
+		s.Next, s.Err = current, current.Err
+	}
+}
+
+// Returns an empty Overlaps list, when reuse is true the current list is truncated and returned.
+func (s *ColumnOverlapAccumulator[E]) emptyOverlaps() *[]*OverlappingSpanSets[E] {
+	if s.reuse && s.Overlaps != nil {
+		*s.Overlaps = (*s.Overlaps)[:0]
+		return s.Overlaps
+	}
+	return &[]*OverlappingSpanSets[E]{}
+}
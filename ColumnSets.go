@@ -1,16 +1,61 @@
 package st
 
 import (
+	"context"
+	"errors"
 	"iter"
 	"slices"
+	"sync"
 )
 
 // Represents a source data set of the culumn consolidation process.
 type CurrentColumn[E any] struct {
 	ColumnOverlap[E]
 	ColumnId int
+
+	// The name of the column, empty when the column was not named, see: AddNamedColumn.
+	Name string
+
+	// The metadata of the column, see: AddNamedColumn.
+	Meta any
 }
 
+// Returns true when the begin value of the column is part of the span.
+// ColumnOverlap implementations that are not a BoundedSpanBoundry are inclusive.
+func (s *CurrentColumn[E]) BeginInclusive() bool {
+	return spanBeginInclusive[E](s.ColumnOverlap)
+}
+
+// Returns true when the end value of the column is part of the span.
+// ColumnOverlap implementations that are not a BoundedSpanBoundry are inclusive.
+func (s *CurrentColumn[E]) EndInclusive() bool {
+	return spanEndInclusive[E](s.ColumnOverlap)
+}
+
+// Denotes which segments are produced by ColumnSets.Iter, based on the columns that overlap with them.
+type JoinMode int
+
+const (
+	// Every segment, including the gaps between columns, where OverlapCount is 0.  This is the default.
+	JoinFullOuter JoinMode = iota
+
+	// Segments where at least one column is present.
+	JoinAny
+
+	// Segments where every open column is present.  Columns that are closed, by RemoveColumn or by a failed column
+	// with ErrorPolicy SkipOnError, are no longer counted.
+	JoinInner
+
+	// Segments where column 0 is present.
+	JoinLeft
+
+	// Segments where column 0 is present, and none of the others are.
+	JoinAnti
+
+	// Segments where at least ColumnSets.MinColumns columns are present.
+	JoinAtLeast
+)
+
 // This struct acts as the majordomo of the constrained span intersection iteration process.
 //
 // For every instance created make sure to scope the proper defer call:
@@ -25,12 +70,62 @@ type ColumnSets[E any] struct {
 	pos     int
 	current *[]*CurrentColumn[E]
 	itr     bool
+	names   map[string]int
+	open    int
+
+	// state of the HeapEngine
+	heap   *columnHeap[E]
+	visit  []int
+	failed []int
+	live   []int
+	spare  []int
+
+	// buffers reused between steps
+	check   []int
+	test    []SpanBoundry[E]
+	span    Span[E]
+	bounded BoundedSpan[E]
+	cached  []*CurrentColumn[E]
+
+	// When true, the first segment is found the same way as the segments after it, see: ShardedColumnSets.
+	// When from is not nil, the first segment begins at from.
+	resume bool
+	from   *boundry[E]
+
+	// the cancel functions of the OlssChanStater columns, called when the context passed to IterContext is done
+	lock     sync.Mutex
+	cancels  []func()
+	canceled bool
 
-	// The last error, nil if there were no errors
+	// The last error, nil if there were no errors.
+	// Errors from a column are reported as a *SpanError[E] with the ColumnId set.
 	Err error
 	// ColumnId the our last error came from
 	ErrCol  int
 	OnClose *[]func()
+
+	// Denotes how errors are handled, default HaltOnError.
+	// When set to SkipOnError, a row of a column that fails with a *SpanError[E] is dropped and the column continues
+	// with its next row, any other error closes the column, and iteration continues with the remaining columns.
+	ErrorPolicy ErrorPolicy
+
+	// When not nil, called for every error, with the ColumnId set.
+	OnError func(err *SpanError[E])
+
+	// Denotes which segments are produced by Iter, default JoinFullOuter.
+	JoinMode JoinMode
+
+	// The minimum number of columns that must be present when JoinMode is JoinAtLeast.
+	MinColumns int
+
+	// Denotes how the next segment is found, default LinearEngine.
+	// Set this before calling Iter.
+	Engine ColumnEngine
+
+	// When true, the buffers used by Iter are reused by every step, so no memory is allocated per segment.
+	// The span, the columns list, the CurrentColumn instances and their overlaps are then only valid until the
+	// loop body returns, copy any values that need to be kept.  Set this before calling Iter.
+	ReuseBuffers bool
 }
 
 type ColumnResults[E any] interface {
@@ -60,6 +155,16 @@ func (s *ColumnSets[E]) GetEnd() E {
 	return s.overlap.GetEnd()
 }
 
+// This is a wrapper for the inclusivity of the s.GetSpan().GetBegin() value.
+func (s *ColumnSets[E]) BeginInclusive() bool {
+	return spanBeginInclusive(s.overlap)
+}
+
+// This is a wrapper for the inclusivity of the s.GetSpan().GetEnd() value.
+func (s *ColumnSets[E]) EndInclusive() bool {
+	return spanEndInclusive(s.overlap)
+}
+
 func (s *ColumnSets[E]) GetColumns() *[]*CurrentColumn[E] {
 	return s.current
 }
@@ -101,29 +206,155 @@ func (s *ColumnSets[E]) OverlapCount() int {
 }
 
 // Appends an a column accumulator to the current column set.
-// Returns the id of the column, if the instance is closed returns -1.
+// Returns the id of the column, if the instance is closed returns -1 and c is closed.
+//
+// Columns can be added while Iter is running.  The column joins from the current segment onward: spans that end
+// before the current segment are dropped, and the column is included starting with the next segment.
 func (s *ColumnSets[E]) AddColumn(c *ColumnOverlapAccumulator[E]) int {
 	if s.closed {
+		if c != nil {
+			c.Close()
+		}
 		return -1
 	}
 	if s.columns == nil {
 		s.columns = &[]*ColumnOverlapAccumulator[E]{}
 	}
 	*s.columns = append(*s.columns, c)
-	return len(*s.columns) - 1
+	var id = len(*s.columns) - 1
+	c.skip = func(err error) bool { return s.rowFailed(id, c, err) }
+	if s.itr {
+		c.reuse = s.ReuseBuffers
+		c.skipNext()
+	}
+	if s.itr && s.pos != -1 && s.overlap != nil && c.HasNext() {
+		// catch up with the current segment
+		c.SetNext(s.overlap)
+	}
+	if s.heap != nil {
+		s.open++
+		if c.Err != nil {
+			s.failed = append(s.failed, id)
+		}
+		if s.heapPush(id) {
+			s.live = append(s.live, id)
+		}
+	}
+	return id
+}
+
+// Detaches the column from the current column set, by closing it, see: ColumnOverlapAccumulator.Close.
+// The column is no longer included in segments produced by Iter, and its name can be reused.
+// The ColumnId values of other columns do not change.
+// Returns false when there is no such column, or the column is already closed.
+func (s *ColumnSets[E]) RemoveColumn(id int) bool {
+	if s.columns == nil || id < 0 || id >= len(*s.columns) {
+		return false
+	}
+	var col = (*s.columns)[id]
+	if col.Closed {
+		return false
+	}
+	col.Close()
+	if s.heap != nil {
+		s.open--
+		if pos, found := slices.BinarySearch(s.live, id); found {
+			s.live = slices.Delete(s.live, pos, pos+1)
+		}
+	}
+	if col.Name != "" && s.names[col.Name] == id {
+		delete(s.names, col.Name)
+	}
+	return true
 }
 
 // This is a helper method that constructs an SpanOverlapAccumulator and then produces
 // an iterator from the SpanOverlapAccumulator based on list.
+//
+// The ErrorPolicy and OnError settings of this instance are passed to the SpanOverlapAccumulator.
 func (s *ColumnSets[E]) AddColumnFromSpanSlice(list *[]SpanBoundry[E]) (int, *SpanOverlapAccumulator[E]) {
+	return s.AddNamedColumnFromSpanSlice("", nil, list)
+}
+
+// Appends a named column accumulator, with metadata, to the current column set.
+// The name and meta values are exposed by CurrentColumn, and the name is used in errors, see: SpanError.
+// Returns the id of the column, if the instance is closed or the name is already in use returns -1 and c is closed.
+// An empty name does not need to be unique, and can not be looked up by ColumnIdOf.
+func (s *ColumnSets[E]) AddNamedColumn(name string, meta any, c *ColumnOverlapAccumulator[E]) int {
+	if s.closed || s.hasName(name) {
+		c.Close()
+		return -1
+	}
+	c.Name = name
+	c.Meta = meta
+	var id = s.AddColumn(c)
+	if name != "" {
+		if s.names == nil {
+			s.names = map[string]int{}
+		}
+		s.names[name] = id
+	}
+	return id
+}
+
+// Same as AddColumnFromSpanSlice, but the column is named, see: AddNamedColumn.
+// When the name is already in use, returns -1 and a nil SpanOverlapAccumulator.
+func (s *ColumnSets[E]) AddNamedColumnFromSpanSlice(name string, meta any, list *[]SpanBoundry[E]) (int, *SpanOverlapAccumulator[E]) {
+	if s.closed || s.hasName(name) {
+		return -1, nil
+	}
 	var ac = s.Util.NewSpanOverlapAccumulator()
-	var res = s.AddColumn(ac.NewCoaFromSbSlice(list))
+	ac.ErrorPolicy = s.ErrorPolicy
+	if s.OnError != nil {
+		// the slice is read before the column is added, so work out the id ahead of time
+		var id = 0
+		if s.columns != nil {
+			id = len(*s.columns)
+		}
+		ac.OnError = func(err *SpanError[E]) {
+			err.ColumnId = id
+			err.ColumnName = name
+			s.OnError(err)
+		}
+	}
+	var res = s.AddNamedColumn(name, meta, ac.NewCoaFromSbSlice(list))
 	return res, ac
 }
 
+func (s *ColumnSets[E]) hasName(name string) bool {
+	if name == "" || s.names == nil {
+		return false
+	}
+	var _, ok = s.names[name]
+	return ok
+}
+
+// Returns the ColumnId of the column with the given name, or -1 when there is no such column.
+func (s *ColumnSets[E]) ColumnIdOf(name string) int {
+	if !s.hasName(name) {
+		return -1
+	}
+	return s.names[name]
+}
+
+// Returns the name of the column, or an empty string when the column does not exist or was not named.
+func (s *ColumnSets[E]) ColumnName(id int) string {
+	if s.columns == nil || id < 0 || id >= len(*s.columns) {
+		return ""
+	}
+	return (*s.columns)[id].Name
+}
+
 // Adds list as a column to the internals.
 func (s *ColumnSets[E]) AddColumnFromOverlappingSpanSets(list *[]*OverlappingSpanSets[E]) int {
-	return s.AddColumn(
+	return s.AddNamedColumnFromOverlappingSpanSets("", nil, list)
+}
+
+// Same as AddColumnFromOverlappingSpanSets, but the column is named, see: AddNamedColumn.
+func (s *ColumnSets[E]) AddNamedColumnFromOverlappingSpanSets(name string, meta any, list *[]*OverlappingSpanSets[E]) int {
+	return s.AddNamedColumn(
+		name,
+		meta,
 		s.Util.NewColumnOverlapAccumulator(
 			iter.Pull2(
 				slices.All(*list),
@@ -140,107 +371,306 @@ func (s *ColumnSets[E]) AddColumnFromOverlappingSpanSets(list *[]*OverlappingSpa
 // it will cause a race condition that will prevent the ColumnSets instancce from working 
 // correctly.
 func (s *ColumnSets[E]) AddColumnFromNewOlssChanStater(sa *OlssChanStater[E]) int {
-	id := s.AddColumn(
-		s.Util.NewColumnOverlapAccumulator(
-			iter.Pull2(
-				s.Util.NewOlssSeq2FromOlssChan(sa.Chan),
-			),
-		),
-	)
-	s.AddOnClose(sa.Shutdown)
+	return s.AddNamedColumnFromNewOlssChanStater("", nil, sa)
+}
+
+// Same as AddColumnFromNewOlssChanStater, but the column is named, see: AddNamedColumn.
+// When the column is not added, sa is shut down, see: OlssChanStater.Shutdown.
+func (s *ColumnSets[E]) AddNamedColumnFromNewOlssChanStater(name string, meta any, sa *OlssChanStater[E]) int {
+	var seq = s.Util.NewOlssSeq2FromOlssChan(sa.Chan)
+	if sa.Ctx != nil {
+		seq = s.Util.olssSeq2FromOlssChanStater(sa)
+	}
+	var col = s.Util.NewColumnOverlapAccumulator(iter.Pull2(seq))
+	col.AddOnClose(sa.Shutdown)
+	var id = s.AddNamedColumn(name, meta, col)
+	if id != -1 && sa.Cancel != nil {
+		s.addCancel(sa.Cancel)
+	}
 	return id
 }
 
+// Registers the cancel function of an OlssChanStater column, cancel is called right away when the
+// context passed to IterContext is already done.
+func (s *ColumnSets[E]) addCancel(cancel func()) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.canceled {
+		cancel()
+		return
+	}
+	s.cancels = append(s.cancels, cancel)
+}
+
+// Cancels every OlssChanStater column, this is called from the go routine of context.AfterFunc.
+func (s *ColumnSets[E]) cancelColumns() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.canceled = true
+	for _, cancel := range s.cancels {
+		cancel()
+	}
+}
+
 func (s *ColumnSets[E]) init() {
-	var check = []int{}
-	var test = &[]SpanBoundry[E]{}
+	var check = s.check[:0]
+	var test = s.test[:0]
 
+	s.open = 0
 	for i, span := range *s.columns {
-		if span.Err != nil {
-			s.Err = span.Err
-			s.ErrCol = i
-			s.pos = -1
-			return
+		span.skipNext()
+		if span.Err != nil && !span.Closed {
+			if s.columnFailed(i, span) {
+				s.pos = -1
+				return
+			}
+			continue
+		}
+		if !span.Closed {
+			s.open++
 		}
 		if span.HasNext() {
 			check = append(check, i)
-			*test = append(*test, span)
+			test = append(test, span)
+		}
+	}
+	s.check, s.test = check, test
+	var begin, end, ok = s.Util.firstBoundries(&s.test)
+	if ok && s.resume {
+		if s.from != nil {
+			begin = *s.from
 		}
+		begin, end, ok = s.Util.boundriesFrom(begin, &s.test)
 	}
-	var init, ok = s.Util.FirstSpan(test)
 	if !ok {
 		s.pos = -1
 		return
 	}
 	s.pos = 0
-	s.overlap = init
-	s.active = &check
+	s.overlap = s.segment(begin, end, &s.test)
+	s.active = &s.check
 	s.setCurrent()
 }
 
+// Returns the span of the segment from begin to end.
+// When ReuseBuffers is true and there is no Tagger, the span is written to a buffer owned by the instance.
+func (s *ColumnSets[E]) segment(begin, end boundry[E], list *[]SpanBoundry[E]) SpanBoundry[E] {
+	var u = s.Util
+	if !s.ReuseBuffers || u.Tagger != nil {
+		return u.tag(u.fromBoundries(begin, end), list)
+	}
+	if begin.bounded || end.bounded || begin.offset != 0 || end.offset != 0 {
+		s.bounded = BoundedSpan[E]{Begin: begin.value, End: end.value, OpenBegin: begin.offset != 0, OpenEnd: end.offset != 0}
+		return &s.bounded
+	}
+	s.span = Span[E]{Begin: begin.value, End: end.value}
+	return &s.span
+}
+
+// Returns true when span is a buffer owned by the instance, that is overwritten by the next step.
+func (s *ColumnSets[E]) buffered(span SpanBoundry[E]) bool {
+	return span == SpanBoundry[E](&s.span) || span == SpanBoundry[E](&s.bounded)
+}
+
+// Reports the error of column i, returns true when iteration should halt.
+// When the ErrorPolicy is SkipOnError the column is closed instead.
+// Errors of a single row never get here under SkipOnError, see: rowFailed.
+func (s *ColumnSets[E]) columnFailed(i int, col *ColumnOverlapAccumulator[E]) bool {
+	var err = columnError[E](col.Err, i)
+	err.ColumnName = col.Name
+	if s.OnError != nil {
+		s.OnError(err)
+	}
+	if s.ErrorPolicy == SkipOnError {
+		col.Close()
+		return false
+	}
+	s.Err = err
+	s.ErrCol = i
+	return true
+}
+
+// Reports the error of a row of column i, returns true when the row should be dropped.
+// Only a *SpanError[E] is an error of a row, any other error fails the whole column, see: columnFailed.
+func (s *ColumnSets[E]) rowFailed(i int, col *ColumnOverlapAccumulator[E], err error) bool {
+	var res *SpanError[E]
+	if s.ErrorPolicy != SkipOnError || !errors.As(err, &res) {
+		return false
+	}
+	res = columnError[E](err, i)
+	res.ColumnName = col.Name
+	if s.OnError != nil {
+		s.OnError(res)
+	}
+	return true
+}
+
 func (s *ColumnSets[E]) setCurrent() {
-	s.current = &[]*CurrentColumn[E]{}
+	s.resetCurrent()
 	for _, i := range *s.active {
 		var col = (*s.columns)[i]
 		col.SetNext(s.overlap)
 		if col.InOverlap() {
-			var res = &CurrentColumn[E]{
-				ColumnId:      i,
-				ColumnOverlap: col,
-			}
-			*s.current = append(*s.current, res)
+			s.appendCurrent(i, col)
+		}
+	}
+}
+
+// Empties the list of current columns, when ReuseBuffers is true the list is reused.
+func (s *ColumnSets[E]) resetCurrent() {
+	if s.ReuseBuffers && s.current != nil {
+		*s.current = (*s.current)[:0]
+		return
+	}
+	s.current = &[]*CurrentColumn[E]{}
+}
+
+// Appends column i to the list of current columns, when ReuseBuffers is true the CurrentColumn of i is reused.
+func (s *ColumnSets[E]) appendCurrent(i int, col *ColumnOverlapAccumulator[E]) {
+	var res *CurrentColumn[E]
+	if s.ReuseBuffers {
+		for len(s.cached) <= i {
+			s.cached = append(s.cached, &CurrentColumn[E]{})
 		}
+		res = s.cached[i]
+	} else {
+		res = &CurrentColumn[E]{}
 	}
+	*res = CurrentColumn[E]{
+		ColumnId:      i,
+		ColumnOverlap: col,
+		Name:          col.Name,
+		Meta:          col.Meta,
+	}
+	*s.current = append(*s.current, res)
 }
 
 func (s *ColumnSets[E]) setNext() {
-	var check = []int{}
-	var test = &[]SpanBoundry[E]{}
+	var check = s.check[:0]
+	var test = s.test[:0]
 
+	s.open = 0
 	for i, span := range *s.columns {
-		if span.Err != nil {
-			s.Err = span.Err
-			s.ErrCol = i
-			s.pos = -1
-			return
+		if span.Err != nil && !span.Closed {
+			if s.columnFailed(i, span) {
+				s.pos = -1
+				return
+			}
+			continue
+		}
+		if !span.Closed {
+			s.open++
 		}
 		if span.HasNext() {
 			check = append(check, i)
-			*test = append(*test, span)
+			test = append(test, span)
 		}
 	}
+	s.check, s.test = check, test
 	if len(check) == 0 {
 		s.pos = -1
 		return
 	}
 
-	var ok bool
-	s.overlap, ok = s.Util.NextSpan(s.overlap, test)
+	var begin, end, ok = s.Util.nextBoundries(s.overlap, &s.test)
 	if !ok {
 		s.pos = -1
 		return
 	}
+	s.overlap = s.segment(begin, end, &s.test)
 	s.pos++
 
-	s.active = &check
+	s.active = &s.check
 	s.setCurrent()
 }
 
+// Returns true when the current segment matches the JoinMode.
+func (s *ColumnSets[E]) joined() bool {
+	var first = -1
+	if len(*s.current) != 0 {
+		first = (*s.current)[0].ColumnId
+	}
+	return s.JoinMode.match(len(*s.current), first, s.open, s.MinColumns)
+}
+
+// Returns true when a segment with count columns, where first is the smallest ColumnId, matches the JoinMode.
+// The open value is the number of columns that have not been closed.
+func (m JoinMode) match(count, first, open, minColumns int) bool {
+	switch m {
+	case JoinAny:
+		return count > 0
+	case JoinInner:
+		return count == open
+	case JoinLeft:
+		return count > 0 && first == 0
+	case JoinAnti:
+		return count == 1 && first == 0
+	case JoinAtLeast:
+		return count >= minColumns
+	}
+	return true
+}
+
 // Creates an iteraotr to walk all added columns and find the overlaps.
+// Only segments that match the JoinMode are produced, the int value counts the segments produced.
+// The ColumnResults yielded is the instance itself, so it changes with every step.  The span and the columns list it
+// returns can be kept, unless ReuseBuffers is true.
 func (s *ColumnSets[E]) Iter() iter.Seq2[int, ColumnResults[E]] {
+	return s.IterContext(context.Background())
+}
+
+// Same as Iter, but the iterator stops once ctx is done, and Err is set to ctx.Err().
+// When ctx is done every column added by AddColumnFromNewOlssChanStater is canceled, so their go routines
+// stop pushing and the iterator is not left waiting on their channels.  Other columns are checked between
+// steps, a column that blocks on its own will delay the iterator until it returns.
+// The columns are closed when the iterator stops, see: Close.
+func (s *ColumnSets[E]) IterContext(ctx context.Context) iter.Seq2[int, ColumnResults[E]] {
 	if s.itr {
 		return nil
 	}
 	s.itr = true
-	s.init()
+	var stop = context.AfterFunc(ctx, s.cancelColumns)
+	s.AddOnClose(func() { stop() })
+	if s.columns != nil {
+		for _, col := range *s.columns {
+			col.reuse = s.ReuseBuffers
+		}
+	}
+	var setNext = s.setNext
+	if s.Engine == HeapEngine {
+		s.heapInit()
+		setNext = s.heapSetNext
+	} else {
+		s.init()
+	}
 
 	return func(yeild func(int, ColumnResults[E]) bool) {
 		defer s.Close()
-		for s.pos != -1 {
-			if !yeild(s.pos, s) {
-				return
+		var id = 0
+		for s.pos != -1 && !s.done(ctx) {
+			if s.joined() {
+				if !yeild(id, s) {
+					return
+				}
+				id++
 			}
-			s.setNext()
+			setNext()
 		}
+		// canceled columns end early, so the last steps may be incomplete
+		s.done(ctx)
+	}
+}
+
+// Returns true when ctx is done, and sets Err to ctx.Err() unless there was already an error.
+func (s *ColumnSets[E]) done(ctx context.Context) bool {
+	var err = ctx.Err()
+	if err == nil {
+		return false
+	}
+	if s.Err == nil {
+		s.Err = err
+		s.ErrCol = -1
 	}
+	s.pos = -1
+	return true
 }
@@ -0,0 +1,121 @@
+package st
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// Returned when the begin value of a span comes after its end value.
+	ErrBeginAfterEnd = errors.New("GetBegin must be less than or equal to GetEnd")
+
+	// Returned when a span comes before the span that preceded it.
+	ErrOutOfSequence = errors.New("SpanBoundry out of sequence")
+
+	// Returned when Prev(Next(x)) is not equal to x.
+	ErrPrevNotNext = errors.New("Prev(Next(x)) must be equal to x")
+
+	// Returned when a span needs to be carved before an inclusive begin value, but there is no Prev function.
+	ErrPrevRequired = errors.New("Prev function is required to carve a span")
+
+	// Returned when a span needs to be carved before the smallest value.
+	ErrNoPrev = errors.New("There is no value before the begin value")
+
+	// Returned when removing a span from a SpanCounter would make the depth of a value negative.
+	ErrNegativeDepth = errors.New("Depth can not be less than 0")
+)
+
+// Denotes how spans that fail validation are handled.
+type ErrorPolicy int
+
+const (
+	// Stop processing at the first error, this is the default.
+	HaltOnError ErrorPolicy = iota
+
+	// Report the error and drop the invalid span, then continue.
+	// In the case of ColumnSets a row that fails with a *SpanError is dropped, any other error drops the column.
+	SkipOnError
+)
+
+// Structured error, used to report which span failed validation and where it came from.
+// Use [errors.Is] to test for the sentinel error, and [errors.As] to inspect the details.
+//
+// Example:
+//
+//	var spanErr *st.SpanError[int]
+//	if errors.As(ac.Err, &spanErr) && errors.Is(spanErr, st.ErrOutOfSequence) {
+//	  fmt.Printf("Column: %d, Pos: %d, Span: %v\n", spanErr.ColumnId, spanErr.Pos, spanErr.Span)
+//	}
+//
+// [errors.Is]: https://pkg.go.dev/errors#Is
+// [errors.As]: https://pkg.go.dev/errors#As
+type SpanError[E any] struct {
+	// The sentinel error, such as ErrOutOfSequence.
+	Err error
+
+	// The span that caused the error, nil when unknown.
+	Span SpanBoundry[E]
+
+	// The span that came before Span, nil when unknown.
+	Previous SpanBoundry[E]
+
+	// Position of Span in the source data set, see: SpanOverlapAccumulator.Pos.
+	// A value of -1 means unknown.
+	Pos int
+
+	// The ColumnId of the source data set in a ColumnSets instance.
+	// A value of -1 means unknown.
+	ColumnId int
+
+	// The name of the column in a ColumnSets instance, empty when the column was not named.
+	ColumnName string
+}
+
+// Creates a new *SpanError[E], with an unknown Pos and ColumnId.
+func NewSpanError[E any](err error, span, previous SpanBoundry[E]) *SpanError[E] {
+	return &SpanError[E]{
+		Err:      err,
+		Span:     span,
+		Previous: previous,
+		Pos:      -1,
+		ColumnId: -1,
+	}
+}
+
+// Returns the sentinel error message, followed by the details that are known.
+func (e *SpanError[E]) Error() string {
+	var msg = e.Err.Error()
+	if e.ColumnName != "" {
+		msg = fmt.Sprintf("%s, ColumnName: %s", msg, e.ColumnName)
+	}
+	if e.ColumnId != -1 {
+		msg = fmt.Sprintf("%s, ColumnId: %d", msg, e.ColumnId)
+	}
+	if e.Pos != -1 {
+		msg = fmt.Sprintf("%s, Pos: %d", msg, e.Pos)
+	}
+	if e.Span != nil {
+		msg = fmt.Sprintf("%s, Span: [%v -> %v]", msg, e.Span.GetBegin(), e.Span.GetEnd())
+	}
+	if e.Previous != nil {
+		msg = fmt.Sprintf("%s, Previous: [%v -> %v]", msg, e.Previous.GetBegin(), e.Previous.GetEnd())
+	}
+	return msg
+}
+
+// Returns the sentinel error.
+func (e *SpanError[E]) Unwrap() error {
+	return e.Err
+}
+
+// Returns a copy of err as a *SpanError[E] with the ColumnId set, so err itself is not changed.
+// When err is not a *SpanError[E], then it is wrapped in one.
+func columnError[E any](err error, id int) *SpanError[E] {
+	var res *SpanError[E]
+	if !errors.As(err, &res) {
+		res = NewSpanError[E](err, nil, nil)
+	}
+	var e = *res
+	e.ColumnId = id
+	return &e
+}
@@ -0,0 +1,87 @@
+package st
+
+import (
+	"iter"
+	"slices"
+)
+
+// Creates an iterator of the sub spans of bounds, that are not covered by any span in seq.
+// The spans in seq must be sorted by their begin values, see: Compare.
+//
+// Finding the end of a gap requires the Prev function, if Prev is nil and a gap ends before
+// an inclusive begin value the iterator yields the error and stops.
+// BoundedSpanBoundry instances do not require a Prev function, their gaps end with an exclusive end value.
+//
+// Example:
+//
+//	for gap, err := range u.Gaps(u.Ns(0, 10), seq) {
+//	  if err != nil {
+//	    return err
+//	  }
+//	  fmt.Printf("Not covered: %v\n", gap)
+//	}
+func (s *SpanUtil[E]) Gaps(bounds SpanBoundry[E], seq iter.Seq[SpanBoundry[E]]) iter.Seq2[SpanBoundry[E], error] {
+	return func(yeild func(SpanBoundry[E], error) bool) {
+		var current = s.fromBoundries(s.beginOf(bounds), s.endOf(bounds))
+		for span := range seq {
+			if s.cmpBoundry(s.endOf(span), s.beginOf(current)) < 0 {
+				continue
+			}
+			if s.cmpBoundry(s.beginOf(span), s.endOf(current)) > 0 {
+				break
+			}
+			var left, right, err = s.carve(current, span)
+			if err != nil {
+				yeild(nil, err)
+				return
+			}
+			if left != nil && !yeild(left, nil) {
+				return
+			}
+			current = right
+			if current == nil {
+				return
+			}
+		}
+		yeild(current, nil)
+	}
+}
+
+// Creates an iterator of the sub spans of bounds that are not covered by any span in list.
+// The list passed in is not modified.  See Gaps for more details.
+func (s *SpanUtil[E]) GapsFromSlice(bounds SpanBoundry[E], list *[]SpanBoundry[E]) iter.Seq2[SpanBoundry[E], error] {
+	return s.Gaps(bounds, slices.Values(*s.Normalize(list)))
+}
+
+// Creates an iterator of the sub spans of bounds that are not covered by any OverlappingSpanSets in seq.
+// When an OverlappingSpanSets contains an error, the iterator yields the error and stops.
+// See Gaps for more details.
+func (s *SpanUtil[E]) GapsFromOlssSeq2(bounds SpanBoundry[E], seq iter.Seq2[int, *OverlappingSpanSets[E]]) iter.Seq2[SpanBoundry[E], error] {
+	return func(yeild func(SpanBoundry[E], error) bool) {
+		// scoped to each run, so ranging over the iterator again does not see an old error
+		var err error
+		var spans = func(yeild func(SpanBoundry[E]) bool) {
+			for _, ol := range seq {
+				if ol.Err != nil {
+					err = ol.Err
+					return
+				}
+				if !yeild(ol) {
+					return
+				}
+			}
+		}
+		for gap, e := range s.Gaps(bounds, spans) {
+			if err != nil {
+				yeild(nil, err)
+				return
+			}
+			if !yeild(gap, e) {
+				return
+			}
+		}
+		if err != nil {
+			yeild(nil, err)
+		}
+	}
+}
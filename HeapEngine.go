@@ -0,0 +1,279 @@
+package st
+
+import (
+	"slices"
+)
+
+// Denotes how ColumnSets finds the next segment.
+type ColumnEngine int
+
+const (
+	// Every step scans every column, this is the default.
+	LinearEngine ColumnEngine = iota
+
+	// Columns are kept in a min-heap ordered by the begin point of their next span, or by its end point once the
+	// span has begun, so each step only visits the columns whose begin or end point is crossed.
+	// Produces the same segments as LinearEngine.
+	HeapEngine
+)
+
+// A column in the heap, and the begin point of its next span, or the end point when end is true.
+type heapEntry[E any] struct {
+	col   int
+	point boundry[E]
+	end   bool
+}
+
+// Min-heap of columns, ordered by point, then begin points before end points, then by ColumnId.
+// The container/heap package is not used, because it boxes every entry.
+type columnHeap[E any] struct {
+	util    *SpanUtil[E]
+	entries []heapEntry[E]
+}
+
+func (h *columnHeap[E]) Len() int {
+	return len(h.entries)
+}
+
+func (h *columnHeap[E]) less(i, j int) bool {
+	var a, b = h.entries[i], h.entries[j]
+	if diff := h.util.cmpBoundry(a.point, b.point); diff != 0 {
+		return diff < 0
+	}
+	if a.end != b.end {
+		return b.end
+	}
+	return a.col < b.col
+}
+
+// Adds e to the heap.
+func (h *columnHeap[E]) push(e heapEntry[E]) {
+	h.entries = append(h.entries, e)
+	var i = len(h.entries) - 1
+	for i > 0 {
+		var parent = (i - 1) / 2
+		if !h.less(i, parent) {
+			break
+		}
+		h.entries[i], h.entries[parent] = h.entries[parent], h.entries[i]
+		i = parent
+	}
+}
+
+// Removes the smallest entry from the heap.
+func (h *columnHeap[E]) pop() {
+	var last = len(h.entries) - 1
+	h.entries[0] = h.entries[last]
+	h.entries = h.entries[:last]
+	var i = 0
+	for {
+		var min = i
+		for _, child := range [2]int{2*i + 1, 2*i + 2} {
+			if child < last && h.less(child, min) {
+				min = child
+			}
+		}
+		if min == i {
+			return
+		}
+		h.entries[i], h.entries[min] = h.entries[min], h.entries[i]
+		i = min
+	}
+}
+
+// Returns the first column in the heap that still has a next span, dropping the others.
+// A begin point is read again, because consolidation can replace the next span with one that has the same
+// begin value, but different bounds.
+func (s *ColumnSets[E]) heapPeek() (heapEntry[E], bool) {
+	for s.heap.Len() != 0 {
+		var top = s.heap.entries[0]
+		var col = (*s.columns)[top.col]
+		if col.HasNext() {
+			if !top.end {
+				top.point = s.Util.beginOf(col)
+			}
+			return top, true
+		}
+		s.heap.pop()
+	}
+	return heapEntry[E]{}, false
+}
+
+// Adds column i to the heap, when it has a next span.
+// Returns true when the next span has begun, those columns are added by the end point of the span and
+// are live, see: heapSetCurrent.
+func (s *ColumnSets[E]) heapPush(i int) bool {
+	var u = s.Util
+	var col = (*s.columns)[i]
+	if !col.HasNext() {
+		return false
+	}
+	if s.overlap == nil || u.cmpBoundry(u.beginOf(col), u.endOf(s.overlap)) > 0 {
+		s.heap.push(heapEntry[E]{col: i, point: u.beginOf(col)})
+		return false
+	}
+	var end = u.endOf(col)
+	if col.Overlaps != nil && len(*col.Overlaps) > 1 {
+		// the sets before the next span are dropped by SetNext, so the next step must visit the column
+		end = u.endOf(s.overlap)
+	}
+	s.heap.push(heapEntry[E]{col: i, point: end, end: true})
+	return true
+}
+
+// Same as init, then every column is added to the heap.
+func (s *ColumnSets[E]) heapInit() {
+	s.heap = &columnHeap[E]{util: s.Util}
+	s.live = s.live[:0]
+	s.init()
+	if s.pos == -1 {
+		return
+	}
+	for i, col := range *s.columns {
+		if col.Err != nil {
+			s.failed = append(s.failed, i)
+		}
+		if s.heapPush(i) {
+			s.live = append(s.live, i)
+		}
+	}
+}
+
+// Same as setNext, but only the columns whose begin or end point is crossed by the next segment are visited.
+func (s *ColumnSets[E]) heapSetNext() {
+	var u = s.Util
+	for _, i := range s.failed {
+		var col = (*s.columns)[i]
+		if col.Err != nil && !col.Closed {
+			if s.columnFailed(i, col) {
+				s.pos = -1
+				return
+			}
+			s.open--
+		}
+	}
+	s.failed = s.failed[:0]
+
+	var min, ok = u.after(u.endOf(s.overlap))
+	if !ok {
+		s.pos = -1
+		return
+	}
+
+	// columns that begin at min, and live columns that end before it
+	var list = s.visit[:0]
+	var ended = 0
+	for {
+		var top, ok = s.heapPeek()
+		if !ok || (top.end && u.cmpBoundry(top.point, min) > -1) || (!top.end && u.cmpBoundry(top.point, min) > 0) {
+			break
+		}
+		s.heap.pop()
+		if top.end {
+			ended++
+		}
+		list = append(list, top.col)
+	}
+	if len(list) == 0 && s.heap.Len() == 0 {
+		s.visit = list
+		s.pos = -1
+		return
+	}
+
+	// ties go to the smallest ColumnId, the same as the linear scan in NextSpan
+	var end boundry[E]
+	var endCol = -1
+	var closer = func(e boundry[E], i int) {
+		if endCol == -1 {
+			end, endCol = e, i
+		} else if diff := u.cmpBoundry(e, end); diff < 0 || (diff == 0 && i < endCol) {
+			end, endCol = e, i
+		}
+	}
+	for _, i := range list {
+		if e := u.endOf((*s.columns)[i]); u.cmpBoundry(e, min) > -1 {
+			closer(e, i)
+		}
+	}
+
+	// Begin points that compare as equal can have different cut points, so every column that begins up to
+	// the point after the end is checked.  Columns that begin inside the segment also overlap with it, and
+	// live columns that end inside the segment are visited.
+	// When no column contains min, the segment is a gap that stops just before the next column begins.
+	var gap = endCol == -1 && ended == len(s.live)
+	for {
+		var top, ok = s.heapPeek()
+		if !ok {
+			break
+		}
+		if endCol != -1 {
+			var limit, ok = u.after(end)
+			if diff := u.cmpBoundry(top.point, limit); ok && (diff > 0 || (top.end && diff == 0)) {
+				break
+			}
+		}
+		s.heap.pop()
+		list = append(list, top.col)
+		if top.end {
+			closer(top.point, top.col)
+		} else if gap {
+			closer(u.gapEnd(top.point, u.cut(top.point, top.point)), top.col)
+		} else {
+			closer(u.cut(top.point, top.point), top.col)
+		}
+	}
+	s.visit = list
+	if endCol == -1 {
+		s.pos = -1
+		return
+	}
+	slices.Sort(list)
+
+	var test = s.test[:0]
+	if u.Tagger != nil {
+		for _, i := range s.live {
+			test = append(test, (*s.columns)[i])
+		}
+		for _, i := range list {
+			if _, found := slices.BinarySearch(s.live, i); !found {
+				test = append(test, (*s.columns)[i])
+			}
+		}
+	}
+	s.test = test
+	s.overlap = s.segment(min, end, &s.test)
+	s.pos++
+	s.heapSetCurrent(list)
+}
+
+// Same as setCurrent, but only the columns in list are visited.
+// The other live columns contain the whole segment, so they are added as they are.
+func (s *ColumnSets[E]) heapSetCurrent(list []int) {
+	s.resetCurrent()
+	var live = s.spare[:0]
+	var k = 0
+	for _, i := range list {
+		for ; k < len(s.live) && s.live[k] <= i; k++ {
+			if s.live[k] != i {
+				s.appendCurrent(s.live[k], (*s.columns)[s.live[k]])
+				live = append(live, s.live[k])
+			}
+		}
+		var col = (*s.columns)[i]
+		col.SetNext(s.overlap)
+		if col.InOverlap() {
+			s.appendCurrent(i, col)
+		}
+		if col.Err != nil {
+			s.failed = append(s.failed, i)
+		}
+		if s.heapPush(i) {
+			live = append(live, i)
+		}
+	}
+	for ; k < len(s.live); k++ {
+		s.appendCurrent(s.live[k], (*s.columns)[s.live[k]])
+		live = append(live, s.live[k])
+	}
+	s.spare, s.live = s.live, live
+}
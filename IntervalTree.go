@@ -0,0 +1,318 @@
+package st
+
+import (
+	"iter"
+)
+
+// Persistent interval tree, used to find the spans that contain a point or overlap with a span.
+// The spans are kept in the order defined by SpanUtil.Compare, the same order used by ColumnSets.
+//
+// Instances are immutable: Insert and Delete return a new tree that shares the unchanged nodes with the
+// original, so older versions of the tree remain valid and are safe to read from multiple go routines.
+//
+// Example:
+//
+//	var tree, _ = u.NewIntervalTreeFromSortedSlice(&[]st.SpanBoundry[int]{u.Ns(1, 5), u.Ns(3, 9)})
+//	tree, _ = tree.Insert(u.Ns(4, 4))
+//	for span := range tree.Stab(4) {
+//	  fmt.Printf("%v contains 4\n", span)
+//	}
+//	// the tree can be used as a column
+//	ac.AddColumnFromSpanSlice(tree.Spans())
+type IntervalTree[E any] struct {
+	Util *SpanUtil[E]
+	root *intervalNode[E]
+
+	// The id given to the next span that is inserted, used to order spans with the same begin and end.
+	seq int
+}
+
+// A node of an IntervalTree, nodes are never modified once they are part of a tree.
+type intervalNode[E any] struct {
+	span  SpanBoundry[E]
+	begin boundry[E]
+	end   boundry[E]
+	id    int
+
+	// The largest end in this sub tree.
+	max boundry[E]
+
+	height int
+	size   int
+	left   *intervalNode[E]
+	right  *intervalNode[E]
+}
+
+// Creates an empty IntervalTree.
+func (s *SpanUtil[E]) NewIntervalTree() *IntervalTree[E] {
+	return &IntervalTree[E]{Util: s}
+}
+
+// Creates a balanced IntervalTree from list, in linear time.
+// The spans in list must be sorted, see: Compare.
+// The error is a *SpanError[E] with Pos set, when a span fails Check.
+func (s *SpanUtil[E]) NewIntervalTreeFromSortedSlice(list *[]SpanBoundry[E]) (*IntervalTree[E], error) {
+	var res = s.NewIntervalTree()
+	if list == nil {
+		return res, nil
+	}
+	var prev SpanBoundry[E]
+	for i, span := range *list {
+		if err := s.Check(span, prev); err != nil {
+			if e, ok := err.(*SpanError[E]); ok {
+				e.Pos = i
+			}
+			return nil, err
+		}
+		prev = span
+	}
+	res.root = res.build(*list, 0)
+	res.seq = len(*list)
+	return res, nil
+}
+
+// Returns a balanced sub tree of the sorted list, where the first span has the id of offset.
+func (s *IntervalTree[E]) build(list []SpanBoundry[E], offset int) *intervalNode[E] {
+	if len(list) == 0 {
+		return nil
+	}
+	var mid = len(list) / 2
+	var n = s.leaf(list[mid], offset+mid)
+	return s.node(n, s.build(list[:mid], offset), s.build(list[mid+1:], offset+mid+1))
+}
+
+// Returns a new node without children.
+func (s *IntervalTree[E]) leaf(span SpanBoundry[E], id int) *intervalNode[E] {
+	var u = s.Util
+	var end = u.endOf(span)
+	return &intervalNode[E]{
+		span:   span,
+		begin:  u.beginOf(span),
+		end:    end,
+		id:     id,
+		max:    end,
+		height: 1,
+		size:   1,
+	}
+}
+
+// Returns a copy of n with the children left and right.
+func (s *IntervalTree[E]) node(n, left, right *intervalNode[E]) *intervalNode[E] {
+	var res = *n
+	res.left = left
+	res.right = right
+	res.max = n.end
+	res.height = 1
+	res.size = 1
+	for _, child := range []*intervalNode[E]{left, right} {
+		if child == nil {
+			continue
+		}
+		if s.Util.cmpBoundry(child.max, res.max) > 0 {
+			res.max = child.max
+		}
+		res.height = max(res.height, child.height+1)
+		res.size += child.size
+	}
+	return &res
+}
+
+func (n *intervalNode[E]) getHeight() int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+// Returns a copy of n with the children left and right, rotated when the heights differ by more than 1.
+func (s *IntervalTree[E]) balance(n, left, right *intervalNode[E]) *intervalNode[E] {
+	var diff = left.getHeight() - right.getHeight()
+	if diff > 1 {
+		if left.left.getHeight() < left.right.getHeight() {
+			left = s.node(left.right, s.node(left, left.left, left.right.left), left.right.right)
+		}
+		return s.node(left, left.left, s.node(n, left.right, right))
+	}
+	if diff < -1 {
+		if right.right.getHeight() < right.left.getHeight() {
+			right = s.node(right.left, right.left.left, s.node(right, right.left.right, right.right))
+		}
+		return s.node(right, s.node(n, left, right.left), right.right)
+	}
+	return s.node(n, left, right)
+}
+
+// Compares the position of node a to node b in the tree.
+func (s *IntervalTree[E]) cmpNode(a, b *intervalNode[E]) int {
+	var u = s.Util
+	var diff = u.cmpBoundry(a.begin, b.begin)
+	if diff == 0 {
+		diff = u.cmpBoundry(b.end, a.end)
+	}
+	if diff == 0 {
+		return a.id - b.id
+	}
+	return diff
+}
+
+// Returns the number of spans in the tree.
+func (s *IntervalTree[E]) Len() int {
+	if s.root == nil {
+		return 0
+	}
+	return s.root.size
+}
+
+// Returns a new tree that also contains span.
+// The error is a *SpanError[E] when span fails Check, and the tree is not changed.
+func (s *IntervalTree[E]) Insert(span SpanBoundry[E]) (*IntervalTree[E], error) {
+	if err := s.Util.Check(span, nil); err != nil {
+		return s, err
+	}
+	var res = *s
+	res.root = s.insert(s.root, s.leaf(span, s.seq))
+	res.seq++
+	return &res, nil
+}
+
+func (s *IntervalTree[E]) insert(n, add *intervalNode[E]) *intervalNode[E] {
+	if n == nil {
+		return add
+	}
+	if s.cmpNode(add, n) < 0 {
+		return s.balance(n, s.insert(n.left, add), n.right)
+	}
+	return s.balance(n, n.left, s.insert(n.right, add))
+}
+
+// Returns a new tree without span, and true when span was found.
+// Spans are matched by their begin and end values; when more than one span matches, the instance
+// that is equal to span is removed, otherwise the first one.
+func (s *IntervalTree[E]) Delete(span SpanBoundry[E]) (*IntervalTree[E], bool) {
+	var key = s.leaf(span, -1)
+	var found *intervalNode[E]
+	for n := range s.equal(s.root, key) {
+		if found == nil || n.span == span {
+			found = n
+		}
+		if n.span == span {
+			break
+		}
+	}
+	if found == nil {
+		return s, false
+	}
+	var res = *s
+	res.root = s.delete(s.root, found)
+	return &res, true
+}
+
+// Returns the nodes with the same begin and end as key, in order.
+func (s *IntervalTree[E]) equal(n, key *intervalNode[E]) iter.Seq[*intervalNode[E]] {
+	var u = s.Util
+	var walk func(n *intervalNode[E], yeild func(*intervalNode[E]) bool) bool
+	walk = func(n *intervalNode[E], yeild func(*intervalNode[E]) bool) bool {
+		if n == nil {
+			return true
+		}
+		var diff = u.cmpBoundry(key.begin, n.begin)
+		if diff == 0 {
+			diff = u.cmpBoundry(n.end, key.end)
+		}
+		if diff <= 0 && !walk(n.left, yeild) {
+			return false
+		}
+		if diff == 0 && !yeild(n) {
+			return false
+		}
+		return diff < 0 || walk(n.right, yeild)
+	}
+	return func(yeild func(*intervalNode[E]) bool) {
+		walk(n, yeild)
+	}
+}
+
+func (s *IntervalTree[E]) delete(n, remove *intervalNode[E]) *intervalNode[E] {
+	var diff = s.cmpNode(remove, n)
+	if diff < 0 {
+		return s.balance(n, s.delete(n.left, remove), n.right)
+	}
+	if diff > 0 {
+		return s.balance(n, n.left, s.delete(n.right, remove))
+	}
+	if n.left == nil {
+		return n.right
+	}
+	if n.right == nil {
+		return n.left
+	}
+	var first = n.right
+	for first.left != nil {
+		first = first.left
+	}
+	return s.balance(first, n.left, s.delete(n.right, first))
+}
+
+// Creates an iterator of the spans that contain point, in order.
+func (s *IntervalTree[E]) Stab(point E) iter.Seq[SpanBoundry[E]] {
+	var p = boundry[E]{value: point}
+	return s.search(p, p)
+}
+
+// Creates an iterator of the spans that overlap with span, in order.
+func (s *IntervalTree[E]) Query(span SpanBoundry[E]) iter.Seq[SpanBoundry[E]] {
+	return s.search(s.Util.beginOf(span), s.Util.endOf(span))
+}
+
+// Creates an iterator of every span in the tree, in order.
+func (s *IntervalTree[E]) All() iter.Seq[SpanBoundry[E]] {
+	return func(yeild func(SpanBoundry[E]) bool) {
+		s.walk(s.root, func(n *intervalNode[E]) bool {
+			return yeild(n.span)
+		})
+	}
+}
+
+// Returns every span in the tree, in order.  The slice returned is a copy, and can be passed to
+// ColumnSets.AddColumnFromSpanSlice.
+func (s *IntervalTree[E]) Spans() *[]SpanBoundry[E] {
+	var res = make([]SpanBoundry[E], 0, s.Len())
+	for span := range s.All() {
+		res = append(res, span)
+	}
+	return &res
+}
+
+// Calls visit for every node in order, until visit returns false.
+func (s *IntervalTree[E]) walk(n *intervalNode[E], visit func(*intervalNode[E]) bool) bool {
+	if n == nil {
+		return true
+	}
+	return s.walk(n.left, visit) && visit(n) && s.walk(n.right, visit)
+}
+
+// Creates an iterator of the spans that overlap with the points begin to end.
+func (s *IntervalTree[E]) search(begin, end boundry[E]) iter.Seq[SpanBoundry[E]] {
+	var u = s.Util
+	var find func(n *intervalNode[E], yeild func(SpanBoundry[E]) bool) bool
+	find = func(n *intervalNode[E], yeild func(SpanBoundry[E]) bool) bool {
+		// nothing in this sub tree ends at or after begin
+		if n == nil || u.cmpBoundry(n.max, begin) < 0 {
+			return true
+		}
+		if !find(n.left, yeild) {
+			return false
+		}
+		// this node, and everything after it, begins after end
+		if u.cmpBoundry(n.begin, end) > 0 {
+			return true
+		}
+		if u.cmpBoundry(begin, n.end) <= 0 && !yeild(n.span) {
+			return false
+		}
+		return find(n.right, yeild)
+	}
+	return func(yeild func(SpanBoundry[E]) bool) {
+		find(s.root, yeild)
+	}
+}
@@ -18,6 +18,10 @@ type OlssChanStater[E any] struct {
 	Ctx        context.Context
 	Cancel     func()
 	IsShutDown bool
+
+	// The cause of the context, set before the channel is closed when the context is done, see: context.Cause.
+	// Read it once the channel is closed, to tell a cancellation apart from the end of the data.
+	Err error
 }
 
 // Acts as a control method in for loops, handles pushing data to the channel from
@@ -29,35 +33,56 @@ type OlssChanStater[E any] struct {
 //  for s.CanAccumulate(span) {
 //    // get your next SpanBoundry
 //  }
+//
+// Returns false once the context is done, see: Push.
 func (s *OlssChanStater[E]) CanAccumulate(span SpanBoundry[E]) bool {
 	if s.Closed {
 		return false
 	}
+	if s.Ctx.Err() != nil {
+		s.abort()
+		return false
+	}
 	if !s.Stater.SetNext(span) {
 		return true
 	}
-	_, next := s.Stater.GetNext()
-	return s.Push(next)
+	for s.Stater.Next != nil {
+		_, next := s.Stater.GetNext()
+		if !s.Push(next) {
+			return false
+		}
+	}
+	return true
 }
 
 // Attempts to push the next value to the channel, if this instance is Closed or
 // if the context has been cancled, then the method returns false.
+//
+// When the context is done the channel is closed, and Err is set to the cause, see: context.Cause.
 func (s *OlssChanStater[E]) Push(next *OverlappingSpanSets[E]) bool {
 	if s.Closed {
 		return false
 	}
 	select {
 	case <-s.Ctx.Done():
-		if !s.Closed {
-			s.Closed=true
-			close(s.Chan)
-		}
+		s.abort()
 		return false
 	case s.Chan <- next:
 		return true
 	}
 }
 
+// Sets Err to the reason the context is done, then closes the channel.
+// Nothing is sent, so the go routine never waits on a consumer that stopped reading.
+func (s *OlssChanStater[E]) abort() {
+	if s.Closed {
+		return
+	}
+	s.Closed = true
+	s.Err = context.Cause(s.Ctx)
+	close(s.Chan)
+}
+
 // Shuts down the context from the Column accumulator thread.  Do not call this outside
 // of the thread running the ColumnOverlapAccumulator instance or you will get undefined
 // behavior.
@@ -75,18 +100,31 @@ func (s *OlssChanStater[E]) Shutdown()  {
 // Example:
 //
 //  defer s.Final()
+//
+// When the context is done, the remaining sets are dropped, see: Push.
 func (s *OlssChanStater[E]) Final() bool {
 	if s.Closed {
 		return false
 	}
+	if s.Ctx.Err() != nil {
+		s.abort()
+		return false
+	}
 
 	res :=false
-	if s.Stater.HasNext() {
+	s.Stater.Flush()
+	for s.Stater.HasNext() {
 		_, ol := s.Stater.GetNext()
 		res= s.Push(ol)
+		if !res {
+			break
+		}
 	}
 
-	close(s.Chan)
-	s.Closed=true
+	// Push closes the channel when the context is done
+	if !s.Closed {
+		close(s.Chan)
+		s.Closed=true
+	}
 	return res
 }
@@ -18,8 +18,12 @@ type OverlappingSpanSets[E any] struct {
 
 	// Ending position in the original data set
 	SrcEnd int
-	
+
+	// When not nil, the source encountered an error, see: SpanError.
 	Err error
+
+	// Source positions of the spans in Contains, only set when spans may have been skipped.
+	srcIds *[]int
 }
 
 // Returns the indexed sequence point of the first original span representing this intersection.
@@ -58,6 +62,21 @@ func (s *OverlappingSpanSets[E]) GetEnd() E {
 	return s.Span.GetEnd()
 }
 
+// Implementation required for this object instance to act as a BoundedSpanBoundry[E] instance.
+func (s *OverlappingSpanSets[E]) BeginInclusive() bool {
+	return spanBeginInclusive(s.Span)
+}
+
+// Implementation required for this object instance to act as a BoundedSpanBoundry[E] instance.
+func (s *OverlappingSpanSets[E]) EndInclusive() bool {
+	return spanEndInclusive(s.Span)
+}
+
+// Returns the Span that contains all Spans in this instance.
+func (s *OverlappingSpanSets[E]) GetSpan() SpanBoundry[E] {
+	return s.Span
+}
+
 // Returns the first span that created this interseciton.
 func (s *OverlappingSpanSets[E]) GetFirstSpan() (int, SpanBoundry[E]) {
 	if s.IsUnique() {
@@ -80,6 +99,11 @@ type OvelapSources[E any] struct {
 	SrcId int
 }
 
+// Returns the SpanBoundry from the orginal data source.
+func (s *OvelapSources[E]) GetSpan() SpanBoundry[E] {
+	return s.SpanBoundry
+}
+
 // Returns all of the spans and thier indexes that caused this current intersection.
 func (s *OverlappingSpanSets[E]) GetSources() *[]*OvelapSources[E] {
 	res := &[]*OvelapSources[E]{}
@@ -90,9 +114,13 @@ func (s *OverlappingSpanSets[E]) GetSources() *[]*OvelapSources[E] {
 		})
 	} else {
 		for id, span := range *s.Contains {
+			var srcId = s.SrcBegin + id
+			if s.srcIds != nil {
+				srcId = (*s.srcIds)[id]
+			}
 			*res = append(*res, &OvelapSources[E]{
 				SpanBoundry: span,
-				SrcId:       s.SrcBegin + id,
+				SrcId:       srcId,
 			})
 		}
 	}
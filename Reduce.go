@@ -0,0 +1,132 @@
+package st
+
+import (
+	"cmp"
+	"iter"
+)
+
+// Creates an iterator that reduces each segment produced by cs.Iter() to a single value of V.
+// Adjacent segments that reduce to the same value are merged into a single span.
+// If cs.Iter() has already been called, then the iterator produced is empty.
+//
+// Example, counting how many columns overlap:
+//
+//	for span, count := range st.Reduce(ac, st.CountColumns[int]()) {
+//	  fmt.Printf("%v -> %v: %d\n", span.GetBegin(), span.GetEnd(), count)
+//	}
+func Reduce[E any, V comparable](cs *ColumnSets[E], reducer func(seg SpanBoundry[E], cols *[]*CurrentColumn[E]) V) iter.Seq2[SpanBoundry[E], V] {
+	var seq = cs.Iter()
+	return func(yeild func(SpanBoundry[E], V) bool) {
+		if seq == nil {
+			return
+		}
+		var u = cs.Util
+		var span SpanBoundry[E]
+		var value V
+		for _, res := range seq {
+			var seg = res.GetSpan()
+			var next = reducer(seg, res.GetColumns())
+			if span != nil && next == value {
+				var begin, ok = u.after(u.endOf(span))
+				if ok && u.cmpBoundry(begin, u.beginOf(seg)) > -1 {
+					span = u.fromBoundries(u.beginOf(span), u.endOf(seg))
+					continue
+				}
+			}
+			if span != nil && !yeild(span, value) {
+				return
+			}
+			span = seg
+			if cs.buffered(seg) {
+				span = u.fromBoundries(u.beginOf(seg), u.endOf(seg))
+			}
+			value = next
+		}
+		if span != nil {
+			yeild(span, value)
+		}
+	}
+}
+
+// Creates a reducer for Reduce, that returns the number of columns that overlap with the segment.
+func CountColumns[E any]() func(seg SpanBoundry[E], cols *[]*CurrentColumn[E]) int {
+	return func(seg SpanBoundry[E], cols *[]*CurrentColumn[E]) int {
+		return len(*cols)
+	}
+}
+
+// Returns the payloads of the source spans of col that overlap with seg, see: Values.
+// A column can hold source spans that only overlap with other source spans of the same column, and not with seg.
+func segmentValues[E, V any](seg SpanBoundry[E], col *CurrentColumn[E]) *[]V {
+	var sources = col.GetSources()
+	if acc, ok := col.ColumnOverlap.(*ColumnOverlapAccumulator[E]); ok && sources != nil {
+		var list = []*OvelapSources[E]{}
+		for _, src := range *sources {
+			if acc.Util.Overlap(src, seg) {
+				list = append(list, src)
+			}
+		}
+		sources = &list
+	}
+	return Values[E, V](sources)
+}
+
+// Creates a reducer for Reduce, that returns the sum of the payloads of every source span that overlaps with the segment, see: TaggedSpan.
+func SumValues[E any, V Number]() func(seg SpanBoundry[E], cols *[]*CurrentColumn[E]) V {
+	return func(seg SpanBoundry[E], cols *[]*CurrentColumn[E]) V {
+		var sum V
+		for _, col := range *cols {
+			for _, value := range *segmentValues[E, V](seg, col) {
+				sum += value
+			}
+		}
+		return sum
+	}
+}
+
+// Creates a reducer for Reduce, that returns the smallest payload of every source span that overlaps with the segment, see: TaggedSpan.
+// When there are no payloads, the zero value of V is returned.
+func MinValue[E any, V cmp.Ordered]() func(seg SpanBoundry[E], cols *[]*CurrentColumn[E]) V {
+	return foldValues[E](func(a, b V) bool { return b < a })
+}
+
+// Creates a reducer for Reduce, that returns the largest payload of every source span that overlaps with the segment, see: TaggedSpan.
+// When there are no payloads, the zero value of V is returned.
+func MaxValue[E any, V cmp.Ordered]() func(seg SpanBoundry[E], cols *[]*CurrentColumn[E]) V {
+	return foldValues[E](func(a, b V) bool { return b > a })
+}
+
+// Creates a reducer that keeps the first payload, unless replace returns true for a later payload.
+func foldValues[E any, V any](replace func(a, b V) bool) func(seg SpanBoundry[E], cols *[]*CurrentColumn[E]) V {
+	return func(seg SpanBoundry[E], cols *[]*CurrentColumn[E]) V {
+		var res V
+		var found = false
+		for _, col := range *cols {
+			for _, value := range *segmentValues[E, V](seg, col) {
+				if !found || replace(res, value) {
+					res = value
+					found = true
+				}
+			}
+		}
+		return res
+	}
+}
+
+// Creates a reducer for Reduce, that returns the ColumnId of the overlapping column with the highest priority.
+// When more than one column has the highest priority, the smallest ColumnId wins.
+// When no column overlaps with the segment, -1 is returned.
+func HighestPriority[E any](priority func(columnId int) int) func(seg SpanBoundry[E], cols *[]*CurrentColumn[E]) int {
+	return func(seg SpanBoundry[E], cols *[]*CurrentColumn[E]) int {
+		var res = -1
+		var best int
+		for _, col := range *cols {
+			var p = priority(col.ColumnId)
+			if res == -1 || p > best || (p == best && col.ColumnId < res) {
+				res = col.ColumnId
+				best = p
+			}
+		}
+		return res
+	}
+}
@@ -0,0 +1,87 @@
+package st
+
+import (
+	"slices"
+	"time"
+)
+
+// Returns span with the begin and end values swapped when the begin value comes after the end value,
+// otherwise span is returned as is.  The inclusivity of each value moves with it.
+func (s *SpanUtil[E]) Repair(span SpanBoundry[E]) SpanBoundry[E] {
+	if s.Cmp(span.GetBegin(), span.GetEnd()) <= 0 {
+		return span
+	}
+	if _, ok := leafSpan(span).(BoundedSpanBoundry[E]); ok {
+		return s.Nb(span.GetEnd(), spanEndInclusive(span), span.GetBegin(), spanBeginInclusive(span))
+	}
+	return s.Ns(span.GetEnd(), span.GetBegin())
+}
+
+// Creates a MaxLateness function for numeric types, that allows spans to arrive up to n units late.
+func Lateness[T Number](n T) func(begin, latest T) bool {
+	return func(begin, latest T) bool {
+		return latest-begin >= n
+	}
+}
+
+// Creates a MaxLateness function for time.Time, that allows spans to arrive up to d late.
+func TimeLateness(d time.Duration) func(begin, latest time.Time) bool {
+	return func(begin, latest time.Time) bool {
+		return latest.Sub(begin) >= d
+	}
+}
+
+// Returns true when reordering is turned on.
+func (s *SpanOverlapAccumulator[E]) reordering() bool {
+	return s.ReorderWindow > 0 || s.MaxLateness != nil
+}
+
+// Adds span to the reorder buffer, and returns the spans that are ready to be accumulated in order.
+func (s *SpanOverlapAccumulator[E]) reorder(span SpanBoundry[E]) []SpanBoundry[E] {
+	if s.RepairInverted {
+		span = s.SpanUtil.Repair(span)
+	}
+	if !s.reordering() {
+		return []SpanBoundry[E]{span}
+	}
+	if s.buffer == nil {
+		s.buffer = &[]SpanBoundry[E]{}
+	}
+	if !s.hasLatest || s.Cmp(span.GetBegin(), s.latest) > 0 {
+		s.latest = span.GetBegin()
+		s.hasLatest = true
+	}
+
+	// insert after any equal spans, so the arrival order is kept
+	var pos, _ = slices.BinarySearchFunc(*s.buffer, span, func(a, b SpanBoundry[E]) int {
+		if s.Compare(a, b) > 0 {
+			return 1
+		}
+		return -1
+	})
+	*s.buffer = slices.Insert(*s.buffer, pos, span)
+
+	var end = 0
+	for end < len(*s.buffer) {
+		if s.ReorderWindow > 0 && len(*s.buffer)-end > s.ReorderWindow {
+			end++
+		} else if s.MaxLateness != nil && s.MaxLateness((*s.buffer)[end].GetBegin(), s.latest) {
+			end++
+		} else {
+			break
+		}
+	}
+	var res = slices.Clone((*s.buffer)[:end])
+	*s.buffer = slices.Delete(*s.buffer, 0, end)
+	return res
+}
+
+// Empties the reorder buffer, and returns the spans it contained in order.
+func (s *SpanOverlapAccumulator[E]) flush() []SpanBoundry[E] {
+	if s.buffer == nil {
+		return nil
+	}
+	var res = *s.buffer
+	s.buffer = nil
+	return res
+}
@@ -0,0 +1,168 @@
+package st
+
+import (
+	"iter"
+	"slices"
+)
+
+// Collects the spans from seq into a slice, the slice can then be passed to any of the set operations.
+func (s *SpanUtil[E]) CollectSpans(seq iter.Seq[SpanBoundry[E]]) *[]SpanBoundry[E] {
+	var res = slices.Collect(seq)
+	if res == nil {
+		res = []SpanBoundry[E]{}
+	}
+	return &res
+}
+
+// Returns a sorted copy of list, where all overlapping spans have been merged into a single span.
+// When Consolidate is true, adjacent spans are merged as well.
+// Spans with a begin value greater than their end value are ignored.
+// The list passed in is never modified.
+func (s *SpanUtil[E]) Normalize(list *[]SpanBoundry[E]) *[]SpanBoundry[E] {
+	var res = []SpanBoundry[E]{}
+	if list == nil || len(*list) == 0 {
+		return &res
+	}
+	var sorted = make([]SpanBoundry[E], 0, len(*list))
+	for _, span := range *list {
+		if s.cmpBoundry(s.beginOf(span), s.endOf(span)) < 1 {
+			sorted = append(sorted, span)
+		}
+	}
+	slices.SortFunc(sorted, s.Compare)
+
+	var ac = s.NewSpanOverlapAccumulator()
+	ac.Validate = false
+	var last *OverlappingSpanSets[E]
+	var ols = []*OverlappingSpanSets[E]{}
+	for _, span := range sorted {
+		var ol, _ = ac.Accumulate(span)
+		if ol != last {
+			ols = append(ols, ol)
+			last = ol
+		}
+	}
+	for _, ol := range ols {
+		res = append(res, ol.Span)
+	}
+	return &res
+}
+
+// Returns the normalized spans that contain every value found in any of the lists.
+func (s *SpanUtil[E]) Union(lists ...*[]SpanBoundry[E]) *[]SpanBoundry[E] {
+	var all = []SpanBoundry[E]{}
+	for _, list := range lists {
+		if list != nil {
+			all = append(all, (*list)...)
+		}
+	}
+	return s.Normalize(&all)
+}
+
+// Returns the normalized spans that contain only the values found in all of the lists.
+func (s *SpanUtil[E]) Intersect(lists ...*[]SpanBoundry[E]) *[]SpanBoundry[E] {
+	if len(lists) == 0 {
+		return &[]SpanBoundry[E]{}
+	}
+	var res = s.Normalize(lists[0])
+	for _, list := range lists[1:] {
+		res = s.intersect(res, s.Normalize(list))
+	}
+	return s.Normalize(res)
+}
+
+// Returns the normalized spans that contain the values found in list, but not in any of the others.
+//
+// Carving a span requires both the Next and Prev functions, if Prev is nil and a span needs to be
+// carved before a removed value, then the returned error is not nil.
+// BoundedSpanBoundry instances do not require a Prev function, they are carved with exclusive end values.
+func (s *SpanUtil[E]) Subtract(list *[]SpanBoundry[E], others ...*[]SpanBoundry[E]) (*[]SpanBoundry[E], error) {
+	return s.subtract(s.Normalize(list), s.Union(others...))
+}
+
+// Returns the normalized spans that contain the values found in an odd number of the lists.
+// When there are only 2 lists, this is every value found in one list but not the other.
+//
+// See Subtract for details on when the error is not nil.
+func (s *SpanUtil[E]) SymmetricDifference(lists ...*[]SpanBoundry[E]) (*[]SpanBoundry[E], error) {
+	var res = &[]SpanBoundry[E]{}
+	for _, list := range lists {
+		var next = s.Normalize(list)
+		var diff, err = s.subtract(s.Union(res, next), s.intersect(res, next))
+		if err != nil {
+			return nil, err
+		}
+		res = diff
+	}
+	return s.Normalize(res), nil
+}
+
+// Intersects 2 normalized lists of spans.
+func (s *SpanUtil[E]) intersect(a, b *[]SpanBoundry[E]) *[]SpanBoundry[E] {
+	var res = []SpanBoundry[E]{}
+	var i, j = 0, 0
+	for i < len(*a) && j < len(*b) {
+		var x, y = (*a)[i], (*b)[j]
+		if s.Overlap(x, y) {
+			var ol, _ = s.CreateOverlapSpan(&[]SpanBoundry[E]{x, y})
+			res = append(res, ol)
+		}
+		if s.cmpBoundry(s.endOf(x), s.endOf(y)) < 0 {
+			i++
+		} else {
+			j++
+		}
+	}
+	return &res
+}
+
+// Removes all values in the normalized list b from the normalized list a.
+func (s *SpanUtil[E]) subtract(a, b *[]SpanBoundry[E]) (*[]SpanBoundry[E], error) {
+	var res = []SpanBoundry[E]{}
+	var j = 0
+	for _, current := range *a {
+		for current != nil && j < len(*b) {
+			var span = (*b)[j]
+			if s.cmpBoundry(s.endOf(span), s.beginOf(current)) < 0 {
+				j++
+				continue
+			}
+			if s.cmpBoundry(s.beginOf(span), s.endOf(current)) > 0 {
+				break
+			}
+			var left, right, err = s.carve(current, span)
+			if err != nil {
+				return nil, err
+			}
+			if left != nil {
+				res = append(res, left)
+			}
+			current = right
+			if right != nil {
+				j++
+			}
+		}
+		if current != nil {
+			res = append(res, current)
+		}
+	}
+	return &res, nil
+}
+
+// Removes b from a, and returns what remains of a on the left and right side of b.
+// A nil left or right value means nothing remains on that side.
+func (s *SpanUtil[E]) carve(a, b SpanBoundry[E]) (SpanBoundry[E], SpanBoundry[E], error) {
+	var left, right SpanBoundry[E]
+	if s.cmpBoundry(s.beginOf(b), s.beginOf(a)) > 0 {
+		var end, err = s.before(s.beginOf(b))
+		if err != nil {
+			return nil, nil, err
+		}
+		left = s.fromBoundries(s.beginOf(a), end)
+	}
+	if s.cmpBoundry(s.endOf(a), s.endOf(b)) > 0 {
+		var begin, _ = s.after(s.endOf(b))
+		right = s.fromBoundries(begin, s.endOf(a))
+	}
+	return left, right, nil
+}
@@ -0,0 +1,666 @@
+package st
+
+import (
+	"context"
+	"iter"
+	"runtime"
+	"slices"
+)
+
+// A column that overlaps with a Segment.
+type SegmentColumn[E any] struct {
+	ColumnId int
+
+	// The name of the column, see: ShardedColumnSets.AddNamedColumnFromSpanSlice.
+	Name string
+
+	// The metadata of the column, see: ShardedColumnSets.AddNamedColumnFromSpanSlice.
+	Meta any
+
+	// The spans from the orginal data source, with their position in the orginal slice.
+	Sources *[]*OvelapSources[E]
+
+	// True when a source continues before or after the shard the segment came from.
+	crossesBegin bool
+	crossesEnd   bool
+
+	// The orginal span of the set the column is in, passed to the Tagger of stitched segments.
+	set SpanBoundry[E]
+}
+
+// A copy of a ColumnSets segment, that is safe to keep after the iteration has moved on.
+type Segment[E any] struct {
+	// The span representing this segment.
+	Span SpanBoundry[E]
+
+	// The columns that overlap with Span, in ColumnId order.
+	Columns *[]*SegmentColumn[E]
+}
+
+// This is a wrapper for s.Span.GetBegin().
+func (s *Segment[E]) GetBegin() E {
+	return s.Span.GetBegin()
+}
+
+// This is a wrapper for s.Span.GetEnd().
+func (s *Segment[E]) GetEnd() E {
+	return s.Span.GetEnd()
+}
+
+// This is a wrapper for the inclusivity of the s.Span.GetBegin() value.
+func (s *Segment[E]) BeginInclusive() bool {
+	return spanBeginInclusive(s.Span)
+}
+
+// This is a wrapper for the inclusivity of the s.Span.GetEnd() value.
+func (s *Segment[E]) EndInclusive() bool {
+	return spanEndInclusive(s.Span)
+}
+
+// Returns the span representing this segment.
+func (s *Segment[E]) GetSpan() SpanBoundry[E] {
+	return s.Span
+}
+
+// Denotes how many columns overlap with this segment.
+func (s *Segment[E]) OverlapCount() int {
+	return len(*s.Columns)
+}
+
+// Runs independent ColumnSets instances over disjoint ranges of E, called shards, on a pool of go routines.
+// The results are stitched back together, in order, into a single iterator.
+//
+// Shard 0 contains everything before Splits[0], shard i contains everything from Splits[i-1] up to,
+// but not including, Splits[i], and the last shard contains everything from the last split onward.
+// Spans that cross a split are cut into pieces, one for each shard they fall into.
+// Within a shard the ends of a piece that were cut at a split are bounded, so segments have exact open or
+// closed ends there, the other ends keep the bounds of the orginal span.
+// A segment that was only split because it crossed a split is stitched back into a single segment, the
+// Sources of the stitched columns are combined.
+type ShardedColumnSets[E any] struct {
+	Util *SpanUtil[E]
+
+	// The values that divide E into shards, see: SampleSplits.
+	Splits []E
+
+	// The number of go routines, when less than 1, runtime.GOMAXPROCS(0) is used.
+	Workers int
+
+	// Denotes which segments are produced by Iter, default JoinFullOuter.
+	JoinMode JoinMode
+
+	// The minimum number of columns that must be present when JoinMode is JoinAtLeast.
+	MinColumns int
+
+	// The last error, nil if there were no errors.
+	Err error
+
+	columns *[]*shardColumn[E]
+	itr     bool
+}
+
+type shardColumn[E any] struct {
+	name string
+	meta any
+	list *[]SpanBoundry[E]
+}
+
+// The part of a source span that falls into a shard.
+type shardSpan[E any] struct {
+	SpanBoundry[E]
+	src          SpanBoundry[E]
+	id           int
+	clippedBegin bool
+	clippedEnd   bool
+
+	// The begin and end points, only the points cut at a split are bounded, see: beginOf.
+	begin boundry[E]
+	end   boundry[E]
+}
+
+func (s *shardSpan[E]) BeginInclusive() bool {
+	return s.begin.offset == 0
+}
+
+func (s *shardSpan[E]) EndInclusive() bool {
+	return s.end.offset == 0
+}
+
+// Returns the span of the piece, it carries the payload of the orginal span, see: SpanUtil.Tagger.
+func (s *shardSpan[E]) GetSpan() SpanBoundry[E] {
+	return s.SpanBoundry
+}
+
+// Returns the shardSpan wrapped by span, or nil when there is none.
+func pieceOf[E any](span SpanBoundry[E]) *shardSpan[E] {
+	for span != nil {
+		if piece, ok := span.(*shardSpan[E]); ok {
+			return piece
+		}
+		var getter, ok = span.(spanGetter[E])
+		if !ok || getter.GetSpan() == span {
+			return nil
+		}
+		span = getter.GetSpan()
+	}
+	return nil
+}
+
+// Factory interface for the creation of ShardedColumnSets[E].
+func (s *SpanUtil[E]) NewShardedColumnSets(splits ...E) *ShardedColumnSets[E] {
+	return &ShardedColumnSets[E]{
+		Util:   s,
+		Splits: splits,
+	}
+}
+
+// Adds list as a column, returns the id of the column.
+// If Iter has already been called returns -1.
+func (s *ShardedColumnSets[E]) AddColumnFromSpanSlice(list *[]SpanBoundry[E]) int {
+	return s.AddNamedColumnFromSpanSlice("", nil, list)
+}
+
+// Adds list as a named column with metadata, returns the id of the column.
+// If Iter has already been called returns -1.
+func (s *ShardedColumnSets[E]) AddNamedColumnFromSpanSlice(name string, meta any, list *[]SpanBoundry[E]) int {
+	if s.itr {
+		return -1
+	}
+	if s.columns == nil {
+		s.columns = &[]*shardColumn[E]{}
+	}
+	if list == nil {
+		list = &[]SpanBoundry[E]{}
+	}
+	*s.columns = append(*s.columns, &shardColumn[E]{name: name, meta: meta, list: list})
+	return len(*s.columns) - 1
+}
+
+// Sets Splits, by sampling the begin values of every column, so each shard holds a similar number of spans.
+func (s *ShardedColumnSets[E]) SampleSplits(shards int) {
+	s.Splits = nil
+	if s.columns == nil || shards < 2 {
+		return
+	}
+	var total = 0
+	for _, col := range *s.columns {
+		total += len(*col.list)
+	}
+	var step = max(1, total/(shards*32))
+	var samples = []E{}
+	var pos = 0
+	for _, col := range *s.columns {
+		for _, span := range *col.list {
+			if pos%step == 0 {
+				samples = append(samples, span.GetBegin())
+			}
+			pos++
+		}
+	}
+	if len(samples) == 0 {
+		return
+	}
+	slices.SortFunc(samples, s.Util.Cmp)
+	for i := 1; i < shards; i++ {
+		s.Splits = append(s.Splits, samples[i*len(samples)/shards])
+	}
+}
+
+// Sorts and validates each column.
+func (s *ShardedColumnSets[E]) prepare() error {
+	var u = s.Util
+	for id, col := range *s.columns {
+		if u.Sort {
+			slices.SortFunc(*col.list, u.Compare)
+		}
+		if !u.Validate {
+			continue
+		}
+		var prev SpanBoundry[E]
+		for pos, span := range *col.list {
+			if err := u.Check(span, prev); err != nil {
+				var spanErr = columnError[E](err, id)
+				spanErr.Pos = pos
+				spanErr.ColumnName = col.name
+				return spanErr
+			}
+			prev = span
+		}
+	}
+	return nil
+}
+
+// Returns the first segment of the whole data set, or nil when there are no spans.
+// The first segment can end at a begin value in any shard, see: SpanUtil.FirstSpan, so it is found up front.
+func (s *ShardedColumnSets[E]) firstSegment() (*Segment[E], error) {
+	var u = *s.Util
+	// the columns are already sorted and validated
+	u.Sort = false
+	u.Validate = false
+	var cs = u.NewColumnSets()
+	defer cs.Close()
+	for _, col := range *s.columns {
+		cs.AddColumnFromSpanSlice(col.list)
+	}
+	cs.JoinMode = JoinFullOuter
+	for _, seg := range cs.Iter() {
+		var cols = &[]*SegmentColumn[E]{}
+		for _, cur := range *seg.GetColumns() {
+			var info = (*s.columns)[cur.ColumnId]
+			var col = &SegmentColumn[E]{
+				ColumnId: cur.ColumnId,
+				Name:     info.name,
+				Meta:     info.meta,
+				Sources:  &[]*OvelapSources[E]{},
+			}
+			for _, set := range *cur.GetOverlaps() {
+				col.set = set.Span
+			}
+			for _, src := range *cur.GetSources() {
+				*col.Sources = append(*col.Sources, &OvelapSources[E]{SpanBoundry: src.SpanBoundry, SrcId: src.SrcId})
+			}
+			*cols = append(*cols, col)
+		}
+		return &Segment[E]{Span: seg.GetSpan(), Columns: cols}, nil
+	}
+	return nil, cs.Err
+}
+
+// Returns the OverlappingSpanSets of each column, indexed by ColumnId.
+// The spans are accumulated before they are cut, so every shard sees the same sets as a single ColumnSets would.
+func (s *ShardedColumnSets[E]) overlapSets() []*[]*OverlappingSpanSets[E] {
+	var u = *s.Util
+	// the columns are already sorted and validated
+	u.Sort = false
+	u.Validate = false
+	var res = make([]*[]*OverlappingSpanSets[E], len(*s.columns))
+	for id, col := range *s.columns {
+		var sets = &[]*OverlappingSpanSets[E]{}
+		for _, set := range u.NewSpanOverlapAccumulator().NewOlssSeq2FromSbSlice(col.list) {
+			*sets = append(*sets, set)
+		}
+		res[id] = sets
+	}
+	return res
+}
+
+// Returns the sorted and unique Splits, and the end point of the shard before each split.
+// A closed span that begins inside a segment also ends it, see: SpanUtil.NextSpan, so a shard can not begin where
+// such a span begins.  Those splits are moved to the next value, or dropped when there is no Next.
+// Without Prev a gap also ends at the begin value of a closed span, so splits in such a gap are moved there first.
+func (s *ShardedColumnSets[E]) shardSplits(columns []*[]*OverlappingSpanSets[E]) ([]E, []boundry[E]) {
+	var u = s.Util
+	var closed, bounded = []E{}, []E{}
+	for _, sets := range columns {
+		for _, set := range *sets {
+			if b := u.beginOf(set); b.offset != 0 {
+				continue
+			} else if b.bounded {
+				bounded = append(bounded, b.value)
+			} else {
+				closed = append(closed, b.value)
+			}
+		}
+	}
+	slices.SortFunc(closed, u.Cmp)
+	slices.SortFunc(bounded, u.Cmp)
+	var res = []E{}
+	for _, split := range s.Splits {
+		for {
+			if _, found := slices.BinarySearchFunc(closed, split, u.Cmp); !found {
+				var begin, gap = s.closedAfterGap(columns, split)
+				if !gap {
+					res = append(res, split)
+					break
+				}
+				split = begin
+				continue
+			}
+			var next, ok = split, u.hasNext()
+			if ok {
+				next, ok = u.next(split)
+			}
+			if !ok {
+				break
+			}
+			split = next
+		}
+	}
+	slices.SortFunc(res, u.Cmp)
+	res = slices.CompactFunc(res, func(a, b E) bool { return u.Cmp(a, b) == 0 })
+
+	// the shard ends the same way a segment would, just before a span that begins at the split
+	var ends = make([]boundry[E], len(res))
+	for i, split := range res {
+		ends[i] = boundry[E]{value: split, offset: -1, bounded: true}
+		if _, found := slices.BinarySearchFunc(bounded, split, u.Cmp); found {
+			var begin = boundry[E]{value: split, bounded: true}
+			ends[i] = u.gapEnd(begin, begin)
+		}
+	}
+	return res, ends
+}
+
+// Returns the begin value of the closed span that ends the gap split is in, the bool value is false when split is
+// not in a gap, the gap does not end at a closed span, or Prev is set.
+func (s *ShardedColumnSets[E]) closedAfterGap(columns []*[]*OverlappingSpanSets[E], split E) (E, bool) {
+	var u = s.Util
+	if u.hasPrev() {
+		return split, false
+	}
+	var point = boundry[E]{value: split}
+	var next boundry[E]
+	var found = false
+	for _, sets := range columns {
+		for _, set := range *sets {
+			var b = u.beginOf(set)
+			if u.cmpBoundry(b, point) < 1 && u.cmpBoundry(u.endOf(set), point) > -1 {
+				return split, false
+			}
+			if u.cmpBoundry(b, point) > 0 && (!found || u.cmpBoundry(b, next) < 0) {
+				next, found = b, true
+			}
+		}
+	}
+	if !found || next.bounded || next.offset != 0 {
+		return split, false
+	}
+	return next.value, true
+}
+
+// Cuts the sets of each column into pieces for each shard, the parts of the sets before from are dropped.
+// Returns the pieces indexed by shard and then by ColumnId.
+func (s *ShardedColumnSets[E]) partition(columns []*[]*OverlappingSpanSets[E], splits []E, ends []boundry[E], from boundry[E]) [][]*[]*OverlappingSpanSets[E] {
+	var u = s.Util
+	var res = make([][]*[]*OverlappingSpanSets[E], len(splits)+1)
+	for i := range res {
+		res[i] = make([]*[]*OverlappingSpanSets[E], len(columns))
+		for id := range columns {
+			res[i][id] = &[]*OverlappingSpanSets[E]{}
+		}
+	}
+	for id, sets := range columns {
+		for _, set := range *sets {
+			var begin, end = u.beginOf(set), u.endOf(set)
+			if u.cmpBoundry(end, from) < 0 {
+				continue
+			}
+			var clipped = false
+			if u.cmpBoundry(from, begin) > 0 {
+				begin, clipped = from, true
+			}
+			for shard := s.shardOf(splits, begin); shard <= len(splits); shard++ {
+				var b, e = begin, end
+				var piece = &shardSpan[E]{src: set.Span, id: set.SrcBegin, clippedBegin: clipped}
+				if shard > 0 {
+					var lo = boundry[E]{value: splits[shard-1], bounded: true}
+					if u.cmpBoundry(lo, b) > 0 {
+						b = lo
+						piece.clippedBegin = true
+					}
+				}
+				var last = shard == len(splits)
+				if !last {
+					if u.cmpBoundry(ends[shard], e) < 0 {
+						e = ends[shard]
+						piece.clippedEnd = true
+					}
+				}
+				if u.cmpBoundry(b, e) <= 0 {
+					var next = set
+					if piece.clippedBegin || piece.clippedEnd {
+						// the ends that were not cut keep the bounds of the set
+						piece.SpanBoundry = u.tag(u.Nb(b.value, b.offset == 0, e.value, e.offset == 0), &[]SpanBoundry[E]{set.Span})
+						piece.begin, piece.end = b, e
+						var cut = *set
+						cut.Span = piece
+						next = &cut
+					}
+					*res[shard][id] = append(*res[shard][id], next)
+				}
+				if !piece.clippedEnd {
+					break
+				}
+			}
+		}
+	}
+	return res
+}
+
+// Returns the index of the shard the point b is in.
+func (s *ShardedColumnSets[E]) shardOf(splits []E, b boundry[E]) int {
+	var u = s.Util
+	var shard, _ = slices.BinarySearchFunc(splits, b, func(split E, b boundry[E]) int {
+		return u.cmpBoundry(boundry[E]{value: split}, b)
+	})
+	if shard < len(splits) && u.cmpBoundry(boundry[E]{value: splits[shard]}, b) == 0 {
+		shard++
+	}
+	return shard
+}
+
+// Runs a ColumnSets instance over the pieces of a single shard.
+// When from is not nil, the shard begins at from, so a gap that follows the first segment is found by the shard.
+func (s *ShardedColumnSets[E]) runShard(ctx context.Context, columns []*[]*OverlappingSpanSets[E], from *boundry[E]) ([]*Segment[E], error) {
+	var cs = s.Util.NewColumnSets()
+	defer cs.Close()
+	// the first segment of the whole data set is found by firstSegment
+	cs.resume = true
+	cs.from = from
+	for _, list := range columns {
+		cs.AddColumnFromOverlappingSpanSets(list)
+	}
+	var res = []*Segment[E]{}
+	for _, seg := range cs.Iter() {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		var cols = &[]*SegmentColumn[E]{}
+		for _, cur := range *seg.GetColumns() {
+			var info = (*s.columns)[cur.ColumnId]
+			var col = &SegmentColumn[E]{
+				ColumnId: cur.ColumnId,
+				Name:     info.name,
+				Meta:     info.meta,
+				Sources:  &[]*OvelapSources[E]{},
+			}
+			for _, set := range *cur.GetOverlaps() {
+				col.set = set.Span
+				if piece := pieceOf(set.Span); piece != nil {
+					col.crossesBegin = col.crossesBegin || piece.clippedBegin
+					col.crossesEnd = col.crossesEnd || piece.clippedEnd
+					col.set = piece.src
+				}
+			}
+			for _, src := range *cur.GetSources() {
+				if piece := pieceOf(src.SpanBoundry); piece != nil {
+					*col.Sources = append(*col.Sources, &OvelapSources[E]{SpanBoundry: piece.src, SrcId: piece.id})
+				} else {
+					*col.Sources = append(*col.Sources, &OvelapSources[E]{SpanBoundry: src.SpanBoundry, SrcId: src.SrcId})
+				}
+			}
+			*cols = append(*cols, col)
+		}
+		res = append(res, &Segment[E]{Span: seg.GetSpan(), Columns: cols})
+	}
+	return res, cs.Err
+}
+
+// Returns true when the segments a and b were only split because they crossed split, end is the end point
+// of the shard before split.
+// Every column of a must cross into b, the other columns of b must begin after the begin of b.  Those columns
+// begin at the end of b, because a closed span that begins inside a segment also ends it, see: SpanUtil.NextSpan.
+func (s *ShardedColumnSets[E]) canStitch(a, b *Segment[E], split E, end boundry[E]) bool {
+	var u = s.Util
+	var next = boundry[E]{value: split}
+	if u.cmpBoundry(u.endOf(a.Span), end) != 0 ||
+		u.cmpBoundry(next, u.beginOf(b.Span)) != 0 || len(*a.Columns) == 0 {
+		return false
+	}
+	var i = 0
+	for _, other := range *b.Columns {
+		if i < len(*a.Columns) && (*a.Columns)[i].ColumnId == other.ColumnId {
+			if !(*a.Columns)[i].crossesEnd || !other.crossesBegin {
+				return false
+			}
+			i++
+			continue
+		}
+		for _, src := range *other.Sources {
+			if u.cmpBoundry(u.beginOf(src), next) < 1 {
+				return false
+			}
+		}
+	}
+	return i == len(*a.Columns)
+}
+
+// Combines the segments a and b into a single segment.
+func (s *ShardedColumnSets[E]) stitch(a, b *Segment[E]) *Segment[E] {
+	var u = s.Util
+	var cols = &[]*SegmentColumn[E]{}
+	var i = 0
+	for _, other := range *b.Columns {
+		if i == len(*a.Columns) || (*a.Columns)[i].ColumnId != other.ColumnId {
+			*cols = append(*cols, other)
+			continue
+		}
+		var res = *(*a.Columns)[i]
+		i++
+		var sources = slices.Clone(*res.Sources)
+		for _, src := range *other.Sources {
+			if !slices.ContainsFunc(sources, func(o *OvelapSources[E]) bool { return o.SrcId == src.SrcId && o.SrcId != -1 }) {
+				sources = append(sources, src)
+			}
+		}
+		res.Sources = &sources
+		res.crossesEnd = other.crossesEnd
+		*cols = append(*cols, &res)
+	}
+	var sets = &[]SpanBoundry[E]{}
+	for _, col := range *cols {
+		if col.set != nil {
+			*sets = append(*sets, col.set)
+		}
+	}
+	return &Segment[E]{Span: u.tag(u.fromBoundries(u.beginOf(a.Span), u.endOf(b.Span)), sets), Columns: cols}
+}
+
+// Returns the segment, with no columns, between a and b from different shards.
+func (s *ShardedColumnSets[E]) gap(a, b *Segment[E]) *Segment[E] {
+	var u = s.Util
+	var begin, ok = u.after(u.endOf(a.Span))
+	if !ok {
+		return nil
+	}
+	var next = u.beginOf(b.Span)
+	var end = u.gapEnd(next, u.cut(next, next))
+	if u.cmpBoundry(begin, next) > -1 || u.cmpBoundry(begin, end) > 0 {
+		return nil
+	}
+	return &Segment[E]{Span: u.tag(u.fromBoundries(begin, end), &[]SpanBoundry[E]{}), Columns: &[]*SegmentColumn[E]{}}
+}
+
+// Creates an iterator that runs every shard, and produces the segments in order.
+// Only segments that match the JoinMode are produced, the int value counts the segments produced.
+// If Iter has already been called, or an error was encountered, the iterator produced is empty, see: Err.
+func (s *ShardedColumnSets[E]) Iter() iter.Seq2[int, *Segment[E]] {
+	return func(yeild func(int, *Segment[E]) bool) {
+		if s.itr || s.columns == nil {
+			return
+		}
+		s.itr = true
+
+		var id = 0
+		var emit = func(seg *Segment[E]) bool {
+			var lowest = -1
+			if len(*seg.Columns) != 0 {
+				lowest = (*seg.Columns)[0].ColumnId
+			}
+			if !s.JoinMode.match(len(*seg.Columns), lowest, len(*s.columns), s.MinColumns) {
+				return true
+			}
+			id++
+			return yeild(id-1, seg)
+		}
+		if err := s.prepare(); err != nil {
+			s.Err = err
+			return
+		}
+		var first, err = s.firstSegment()
+		if err != nil || first == nil {
+			s.Err = err
+			return
+		}
+		var from, ok = s.Util.after(s.Util.endOf(first.Span))
+		if !ok {
+			emit(first)
+			return
+		}
+		var sets = s.overlapSets()
+		var splits, ends = s.shardSplits(sets)
+		var shards = s.partition(sets, splits, ends, from)
+
+		var ctx, cancel = context.WithCancel(context.Background())
+		defer cancel()
+		type result struct {
+			segments []*Segment[E]
+			err      error
+		}
+		// the shard that from is in begins at from, see: runShard
+		var fromShard = s.shardOf(splits, from)
+		var jobs = make(chan int, len(shards))
+		var results = make([]chan result, len(shards))
+		for i := range shards {
+			results[i] = make(chan result, 1)
+			jobs <- i
+		}
+		close(jobs)
+		var workers = s.Workers
+		if workers < 1 {
+			workers = runtime.GOMAXPROCS(0)
+		}
+		for range min(workers, len(shards)) {
+			go func() {
+				for i := range jobs {
+					var start *boundry[E]
+					if i == fromShard {
+						start = &from
+					}
+					var segments, err = s.runShard(ctx, shards[i], start)
+					results[i] <- result{segments, err}
+				}
+			}()
+		}
+
+		// the columns of the first segment never cross into a shard, so it is not stitched
+		var prev = first
+		for i := range shards {
+			var res = <-results[i]
+			if res.err != nil {
+				s.Err = res.err
+				return
+			}
+			for pos, seg := range res.segments {
+				if prev != nil && pos == 0 {
+					if i > 0 && s.canStitch(prev, seg, splits[i-1], ends[i-1]) {
+						prev = s.stitch(prev, seg)
+						continue
+					}
+					// gaps are only produced by JoinFullOuter
+					if gap := s.gap(prev, seg); gap != nil && s.JoinMode == JoinFullOuter {
+						if !emit(prev) {
+							return
+						}
+						prev = gap
+					}
+				}
+				if prev != nil && !emit(prev) {
+					return
+				}
+				prev = seg
+			}
+		}
+		emit(prev)
+	}
+}
@@ -0,0 +1,187 @@
+package st
+
+import (
+	"iter"
+	"slices"
+	"sort"
+)
+
+// Counts how many spans cover each value, as a step function.
+// Every call to Add increments the depth of the values in the span, and every call to Remove decrements it.
+// Instances are not safe for concurrent use.
+//
+// Example:
+//
+//	var counter = u.NewSpanCounter()
+//	counter.Add(u.Ns(1, 10))
+//	counter.Add(u.Ns(5, 20))
+//	fmt.Println(counter.Depth(7), counter.MaxDepth(u.Ns(11, 30)))
+//	for span, depth := range counter.Segments() {
+//	  fmt.Printf("%v -> %v: %d\n", span.GetBegin(), span.GetEnd(), depth)
+//	}
+type SpanCounter[E any] struct {
+	Util *SpanUtil[E]
+
+	// The points where the depth changes, in order.
+	steps []counterStep[E]
+
+	// The end of spans that end at the largest value, where there is no point after the end.
+	last boundry[E]
+}
+
+// From the begin point at, until the next step, every value has the same depth.
+type counterStep[E any] struct {
+	at    boundry[E]
+	depth int
+}
+
+// Creates an empty SpanCounter.
+func (s *SpanUtil[E]) NewSpanCounter() *SpanCounter[E] {
+	return &SpanCounter[E]{Util: s, steps: []counterStep[E]{}}
+}
+
+// Increments the depth of every value in span.
+// The error is a *SpanError[E] when span fails Check, and the counter is not changed.
+func (s *SpanCounter[E]) Add(span SpanBoundry[E]) error {
+	if err := s.Util.Check(span, nil); err != nil {
+		return err
+	}
+	s.update(span, 1)
+	return nil
+}
+
+// Decrements the depth of every value in span.
+// The error is a *SpanError[E] when span fails Check, or when the depth of a value in span is 0, see: ErrNegativeDepth.
+// When the error is not nil the counter is not changed.
+func (s *SpanCounter[E]) Remove(span SpanBoundry[E]) error {
+	if err := s.Util.Check(span, nil); err != nil {
+		return err
+	}
+	if s.minDepth(span) < 1 {
+		return NewSpanError(ErrNegativeDepth, span, nil)
+	}
+	s.update(span, -1)
+	return nil
+}
+
+// Returns the index of the first step at or after p.
+func (s *SpanCounter[E]) search(p boundry[E]) int {
+	return sort.Search(len(s.steps), func(i int) bool {
+		return s.Util.cmpBoundry(s.steps[i].at, p) > -1
+	})
+}
+
+// Returns the depth at the point p.
+func (s *SpanCounter[E]) depthAt(p boundry[E]) int {
+	var i = s.search(p)
+	if i < len(s.steps) && s.Util.cmpBoundry(s.steps[i].at, p) == 0 {
+		return s.steps[i].depth
+	}
+	if i == 0 {
+		return 0
+	}
+	return s.steps[i-1].depth
+}
+
+// Returns the index of the step at p, the step is added when there is none.
+func (s *SpanCounter[E]) split(p boundry[E]) int {
+	var i = s.search(p)
+	if i < len(s.steps) && s.Util.cmpBoundry(s.steps[i].at, p) == 0 {
+		return i
+	}
+	s.steps = slices.Insert(s.steps, i, counterStep[E]{at: p, depth: s.depthAt(p)})
+	return i
+}
+
+// Adds delta to the depth of every value in span, then drops the steps that do not change the depth.
+func (s *SpanCounter[E]) update(span SpanBoundry[E], delta int) {
+	var u = s.Util
+	var first = s.split(u.beginOf(span))
+	var last = len(s.steps)
+	if next, ok := u.after(u.endOf(span)); ok {
+		last = s.split(next)
+	} else {
+		s.last = u.endOf(span)
+	}
+	for i := first; i < last; i++ {
+		s.steps[i].depth += delta
+	}
+	var res = s.steps[:0]
+	var depth = 0
+	for _, step := range s.steps {
+		if step.depth != depth {
+			res = append(res, step)
+			depth = step.depth
+		}
+	}
+	s.steps = res
+}
+
+// Returns the number of spans that cover point.
+func (s *SpanCounter[E]) Depth(point E) int {
+	return s.depthAt(boundry[E]{value: point})
+}
+
+// Returns the largest depth of any value in span.
+func (s *SpanCounter[E]) MaxDepth(span SpanBoundry[E]) int {
+	var res = 0
+	for depth := range s.depths(span) {
+		res = max(res, depth)
+	}
+	return res
+}
+
+// Returns the smallest depth of any value in span.
+func (s *SpanCounter[E]) minDepth(span SpanBoundry[E]) int {
+	var res = -1
+	for depth := range s.depths(span) {
+		if res == -1 || depth < res {
+			res = depth
+		}
+	}
+	return res
+}
+
+// Creates an iterator of the depths of the steps that overlap with span.
+func (s *SpanCounter[E]) depths(span SpanBoundry[E]) iter.Seq[int] {
+	var u = s.Util
+	var begin, end = u.beginOf(span), u.endOf(span)
+	return func(yeild func(int) bool) {
+		if !yeild(s.depthAt(begin)) {
+			return
+		}
+		for i := s.search(begin); i < len(s.steps) && u.cmpBoundry(s.steps[i].at, end) < 1; i++ {
+			if !yeild(s.steps[i].depth) {
+				return
+			}
+		}
+	}
+}
+
+// Creates an iterator of the segments where the depth does not change, and their depth.
+// Values with a depth of 0 are not included.
+//
+// When Prev is nil, a segment that ends before an inclusive begin value has an exclusive end value,
+// see: BoundedSpanBoundry.
+func (s *SpanCounter[E]) Segments() iter.Seq2[SpanBoundry[E], int] {
+	var u = s.Util
+	return func(yeild func(SpanBoundry[E], int) bool) {
+		for i, step := range s.steps {
+			if step.depth == 0 {
+				continue
+			}
+			var end = s.last
+			if i+1 < len(s.steps) {
+				var next = s.steps[i+1].at
+				var err error
+				if end, err = u.before(next); err != nil {
+					next.bounded = true
+					end, _ = u.before(next)
+				}
+			}
+			if !yeild(u.fromBoundries(step.at, end), step.depth) {
+				return
+			}
+		}
+	}
+}
@@ -6,21 +6,54 @@ type SpanIterSeq2Stater[E any] struct {
 	Next    *OverlappingSpanSets[E]
 	Sa      *SpanOverlapAccumulator[E]
 	Id      int
+
+	// Completed sets after Next, only used when the reorder buffer releases more than one span at a time.
+	pending *[]*OverlappingSpanSets[E]
 }
 
 // Returns true if this SpanBoundry[E] created a new data intersrection.
 // If the value is true you must make a call to s.GetNext() instance method before calling this method again!
 func (s *SpanIterSeq2Stater[E]) SetNext(span SpanBoundry[E]) bool {
-	cmp,_ := s.Sa.Accumulate(span)
+	for _, next := range s.Sa.reorder(span) {
+		s.accumulate(next)
+	}
+	return s.Next != nil
+}
+
+// Releases any spans held in the reorder buffer, call this method once there are no more spans.
+// Returns true if a new data intersection was created, see: SetNext.
+func (s *SpanIterSeq2Stater[E]) Flush() bool {
+	for _, next := range s.Sa.flush() {
+		s.accumulate(next)
+	}
+	return s.Next != nil
+}
+
+func (s *SpanIterSeq2Stater[E]) accumulate(span SpanBoundry[E]) {
+	cmp, _ := s.Sa.Accumulate(span)
+	if cmp.Span == nil {
+		// the span was skipped, and there is nothing to report yet
+		return
+	}
 	if s.Current == nil {
 		s.Current = cmp
-		return false
+		return
+	}
+	if s.Next == nil {
+		if s.Current != cmp {
+			s.Next = cmp
+		}
+		return
 	}
-	if s.Current == cmp {
-		return false
+	if s.pending == nil {
+		if s.Next != cmp {
+			s.pending = &[]*OverlappingSpanSets[E]{cmp}
+		}
+		return
+	}
+	if (*s.pending)[len(*s.pending)-1] != cmp {
+		*s.pending = append(*s.pending, cmp)
 	}
-	s.Next = cmp
-	return true
 }
 
 // Returns true if we have any more intersections.
@@ -33,8 +66,15 @@ func (s *SpanIterSeq2Stater[E]) GetNext() (int, *OverlappingSpanSets[E]) {
 	var next = s.Current
 	s.Current = s.Next
 	s.Next = nil
+	if s.pending != nil {
+		s.Next = (*s.pending)[0]
+		if len(*s.pending) == 1 {
+			s.pending = nil
+		} else {
+			*s.pending = (*s.pending)[1:]
+		}
+	}
 	s.Id++
 
 	return s.Id, next
 }
-
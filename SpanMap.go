@@ -0,0 +1,204 @@
+package st
+
+import (
+	"iter"
+	"slices"
+	"sort"
+)
+
+// Sorted map of disjoint spans to values, that can be changed in any order.
+// Setting a value for a span overwrites the values of every range it overlaps with, splitting them when needed.
+// Instances are not safe for concurrent use.
+//
+// When Equal is not nil, adjacent ranges with equal values are coalesced into a single range.
+//
+// Example:
+//
+//	var prices = st.NewSpanMap(u, func(a, b float64) bool { return a == b })
+//	prices.Set(u.HalfOpen(1, 100), 9.99)
+//	prices.Set(u.HalfOpen(10, 20), 4.99)
+//	// prices now contains: [1 -> 10) 9.99, [10 -> 20) 4.99, [20 -> 100) 9.99
+//	var price, ok = prices.Get(15)
+//	for span, price := range prices.Range(u.HalfOpen(5, 15)) {
+//	  fmt.Printf("%v -> %v: %v\n", span.GetBegin(), span.GetEnd(), price)
+//	}
+type SpanMap[E, V any] struct {
+	Util *SpanUtil[E]
+
+	// Used to coalesce adjacent ranges, when nil adjacent ranges are never coalesced.
+	Equal func(a, b V) bool
+
+	entries []mapEntry[E, V]
+}
+
+// A range of a SpanMap and its value.
+type mapEntry[E, V any] struct {
+	span  SpanBoundry[E]
+	value V
+}
+
+// Creates an empty SpanMap, equal is used to coalesce adjacent ranges and can be nil.
+func NewSpanMap[E, V any](u *SpanUtil[E], equal func(a, b V) bool) *SpanMap[E, V] {
+	return &SpanMap[E, V]{Util: u, Equal: equal, entries: []mapEntry[E, V]{}}
+}
+
+// Returns the index of the first range where test returns true.
+func (s *SpanMap[E, V]) search(test func(span SpanBoundry[E]) bool) int {
+	return sort.Search(len(s.entries), func(i int) bool {
+		return test(s.entries[i].span)
+	})
+}
+
+// Returns the range of indexes of the ranges that overlap with span.
+func (s *SpanMap[E, V]) overlaps(span SpanBoundry[E]) (int, int) {
+	var u = s.Util
+	var begin, end = u.beginOf(span), u.endOf(span)
+	var first = s.search(func(x SpanBoundry[E]) bool {
+		return u.cmpBoundry(u.endOf(x), begin) > -1
+	})
+	var last = s.search(func(x SpanBoundry[E]) bool {
+		return u.cmpBoundry(u.beginOf(x), end) > 0
+	})
+	return first, max(first, last)
+}
+
+// Returns what remains of the ranges first to last, once span is removed from them.
+func (s *SpanMap[E, V]) cut(first, last int, span SpanBoundry[E]) ([]mapEntry[E, V], []mapEntry[E, V], error) {
+	var left, right = []mapEntry[E, V]{}, []mapEntry[E, V]{}
+	for _, entry := range s.entries[first:last] {
+		var l, r, err = s.Util.carve(entry.span, span)
+		if err != nil {
+			return nil, nil, err
+		}
+		if l != nil {
+			left = append(left, mapEntry[E, V]{span: l, value: entry.value})
+		}
+		if r != nil {
+			right = append(right, mapEntry[E, V]{span: r, value: entry.value})
+		}
+	}
+	return left, right, nil
+}
+
+// Sets the value of every point in span to value, overwriting the values of the ranges it overlaps with.
+//
+// The error is a *SpanError[E] when span fails Check, or ErrPrevRequired when an existing range
+// must end just before span and Prev is nil.  The map is not changed when the error is not nil.
+func (s *SpanMap[E, V]) Set(span SpanBoundry[E], value V) error {
+	var u = s.Util
+	if err := u.Check(span, nil); err != nil {
+		return err
+	}
+	var first, last = s.overlaps(span)
+	var left, right, err = s.cut(first, last, span)
+	if err != nil {
+		return err
+	}
+	var list = append(left, mapEntry[E, V]{span: u.fromBoundries(u.beginOf(span), u.endOf(span)), value: value})
+	list = append(list, right...)
+	s.entries = slices.Replace(s.entries, first, last, list...)
+	s.coalesce(first, first+len(list))
+	return nil
+}
+
+// Removes every point in span from the map, splitting the ranges it overlaps with.
+//
+// See Set for details on when the error is not nil, the map is not changed when it is.
+func (s *SpanMap[E, V]) Delete(span SpanBoundry[E]) error {
+	var first, last = s.overlaps(span)
+	if first == last {
+		return nil
+	}
+	var left, right, err = s.cut(first, last, span)
+	if err != nil {
+		return err
+	}
+	s.entries = slices.Replace(s.entries, first, last, append(left, right...)...)
+	return nil
+}
+
+// Returns true when the range b begins just after a ends, and their values are equal.
+func (s *SpanMap[E, V]) adjacent(a, b mapEntry[E, V]) bool {
+	var u = s.Util
+	var next, ok = u.after(u.endOf(a.span))
+	return ok && u.cmpBoundry(next, u.beginOf(b.span)) == 0 && s.Equal(a.value, b.value)
+}
+
+// Coalesces the ranges first to last with their neighbours, when Equal is not nil.
+func (s *SpanMap[E, V]) coalesce(first, last int) {
+	if s.Equal == nil {
+		return
+	}
+	var u = s.Util
+	first = max(first-1, 0)
+	last = min(last+1, len(s.entries))
+	if last-first < 2 {
+		return
+	}
+	var res = s.entries[:first+1]
+	for _, entry := range s.entries[first+1 : last] {
+		var prev = &res[len(res)-1]
+		if s.adjacent(*prev, entry) {
+			prev.span = u.fromBoundries(u.beginOf(prev.span), u.endOf(entry.span))
+			continue
+		}
+		res = append(res, entry)
+	}
+	s.entries = append(res, s.entries[last:]...)
+}
+
+// Returns the value of point, the bool value is false when point is not in the map.
+func (s *SpanMap[E, V]) Get(point E) (V, bool) {
+	var i = s.search(func(x SpanBoundry[E]) bool {
+		return s.Util.cmpBoundry(s.Util.endOf(x), boundry[E]{value: point}) > -1
+	})
+	if i < len(s.entries) && s.Util.Contains(s.entries[i].span, point) {
+		return s.entries[i].value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Returns the number of disjoint ranges in the map.
+func (s *SpanMap[E, V]) Len() int {
+	return len(s.entries)
+}
+
+// Creates an iterator of the ranges in the map that overlap with span, and their values, in order.
+// The ranges are clipped to the begin and end values of span.
+// The map must not be changed while iterating.
+func (s *SpanMap[E, V]) Range(span SpanBoundry[E]) iter.Seq2[SpanBoundry[E], V] {
+	var u = s.Util
+	var begin, end = u.beginOf(span), u.endOf(span)
+	return func(yeild func(SpanBoundry[E], V) bool) {
+		var first, last = s.overlaps(span)
+		for _, entry := range s.entries[first:last] {
+			var b, e = u.beginOf(entry.span), u.endOf(entry.span)
+			var res = entry.span
+			if u.cmpBoundry(b, begin) < 0 || u.cmpBoundry(e, end) > 0 {
+				if u.cmpBoundry(b, begin) < 0 {
+					b = begin
+				}
+				if u.cmpBoundry(e, end) > 0 {
+					e = end
+				}
+				res = u.fromBoundries(b, e)
+			}
+			if !yeild(res, entry.value) {
+				return
+			}
+		}
+	}
+}
+
+// Creates an iterator of every range in the map, and their values, in order.
+// The map must not be changed while iterating.
+func (s *SpanMap[E, V]) All() iter.Seq2[SpanBoundry[E], V] {
+	return func(yeild func(SpanBoundry[E], V) bool) {
+		for _, entry := range s.entries {
+			if !yeild(entry.span, entry.value) {
+				return
+			}
+		}
+	}
+}
@@ -25,6 +25,38 @@ type SpanOverlapAccumulator[E any] struct {
 
 	// Turns consolidation of adjacent spans on or off, default false or off
 	Consolidate bool
+
+	// When not nil, called whenever span b is merged into the current span a, either because they overlap or
+	// because they were joined by consolidation.  The merged value holds the combined bounds, and the returned
+	// span replaces Rss.Span, see: MergeValues.
+	Merge func(merged, a, b SpanBoundry[E]) SpanBoundry[E]
+
+	// Denotes how spans that fail validation are handled, default HaltOnError.
+	ErrorPolicy ErrorPolicy
+
+	// When not nil, called for every span that fails validation.
+	// Example of routing invalid spans to a dead letter channel:
+	//
+	//  ac.ErrorPolicy = st.SkipOnError
+	//  ac.OnError = func(err *st.SpanError[int]) { deadLetters <- err }
+	OnError func(err *SpanError[E])
+
+	// When greater than 0, up to ReorderWindow spans are buffered and sorted, with SpanUtil.Compare,
+	// before they are accumulated.  This allows slightly out of order streams to pass validation.
+	// Only applies to spans passed in via the iterator and channel factories, not to Accumulate.
+	ReorderWindow int
+
+	// When not nil, buffered spans are released once MaxLateness returns true for their begin value and the
+	// largest begin value seen so far.  See: Lateness and TimeLateness.
+	// Can be combined with ReorderWindow, which then acts as an upper limit on the size of the buffer.
+	MaxLateness func(begin, latest E) bool
+
+	// When true, spans with a begin value after their end value are swapped before they are buffered, see: SpanUtil.Repair.
+	RepairInverted bool
+
+	buffer    *[]SpanBoundry[E]
+	latest    E
+	hasLatest bool
 }
 
 // The Accumulate method.
@@ -34,10 +66,23 @@ type SpanOverlapAccumulator[E any] struct {
 // When the span is outside of the current internal span,
 // then a new OverlappingSpanSets is created with this span as its current span.
 // The error value is nil, by default, when an error has happend it is no longer nil.
+//
+// When ErrorPolicy is SkipOnError, spans that fail validation are reported to OnError and dropped,
+// the current OverlappingSpanSets is returned unchanged and the error value remains nil.
 func (s *SpanOverlapAccumulator[E]) Accumulate(span SpanBoundry[E]) (*OverlappingSpanSets[E], error) {
 	s.Pos++
 	if s.Validate && s.Err==nil {
-		s.Err = s.Check(span, s.Rss.Span)
+		var err = s.Check(span, s.Rss.Span)
+		if e, ok := err.(*SpanError[E]); ok {
+			e.Pos = s.Pos
+			if s.OnError != nil {
+				s.OnError(e)
+			}
+			if s.ErrorPolicy == SkipOnError {
+				return s.Rss, nil
+			}
+		}
+		s.Err = err
 	}
 
 	if s.Err != nil {
@@ -46,16 +91,18 @@ func (s *SpanOverlapAccumulator[E]) Accumulate(span SpanBoundry[E]) (*Overlappin
 
 	if s.Rss.Span == nil {
 		s.Rss.Span = span
+		s.Rss.SrcBegin = s.Pos
+		s.Rss.SrcEnd = s.Pos
 		return s.Rss, s.Err
 	}
 
 	a := s.Rss.Span
-	if s.Cmp(a.GetEnd(), span.GetBegin()) < 0 {
+	if s.cmpBoundry(s.endOf(a), s.beginOf(span)) < 0 {
 		var joined = false
 		if s.Consolidate {
-			var next = s.Next(a.GetEnd())
-			if s.Cmp(next, span.GetBegin()) == 0 {
-				s.Rss.Span = s.Ns(a.GetBegin(),span.GetEnd())
+			var next, ok = s.after(s.endOf(a))
+			if ok && s.cmpBoundry(next, s.beginOf(span)) == 0 {
+				s.Rss.Span = s.merge(s.fromBoundries(s.beginOf(a), s.endOf(span)), a, span)
 				joined = true
 			}
 		}
@@ -72,24 +119,32 @@ func (s *SpanOverlapAccumulator[E]) Accumulate(span SpanBoundry[E]) (*Overlappin
 	} else {
 		x, y := s.ContainedBy(a, span)
 		if x|y != 0 {
-			var begin,end E;
+			var begin,end boundry[E];
 			if x < 0 {
-				begin = a.GetBegin()
+				begin = s.beginOf(a)
 			} else {
-				begin = span.GetBegin()
+				begin = s.beginOf(span)
 			}
 			if y > 0 {
-				end = a.GetEnd()
+				end = s.endOf(a)
 			} else {
-				end = span.GetEnd()
+				end = s.endOf(span)
 			}
-			s.Rss.Span = s.Ns(begin,end)
+			s.Rss.Span = s.fromBoundries(begin,end)
 		}
+		s.Rss.Span = s.merge(s.Rss.Span, a, span)
 
 		if s.Rss.Contains == nil {
 			s.Rss.Contains = &[]SpanBoundry[E]{a, span}
+			if s.ErrorPolicy == SkipOnError {
+				// skipped spans leave holes in the source positions
+				s.Rss.srcIds = &[]int{s.Rss.SrcBegin, s.Pos}
+			}
 		} else {
 			*s.Rss.Contains = append(*s.Rss.Contains, span)
+			if s.Rss.srcIds != nil {
+				*s.Rss.srcIds = append(*s.Rss.srcIds, s.Pos)
+			}
 		}
 		s.Rss.SrcEnd = s.Pos
 	}
@@ -98,6 +153,14 @@ func (s *SpanOverlapAccumulator[E]) Accumulate(span SpanBoundry[E]) (*Overlappin
 
 
 
+// Calls the Merge hook when set, otherwise returns merged.
+func (s *SpanOverlapAccumulator[E]) merge(merged, a, b SpanBoundry[E]) SpanBoundry[E] {
+	if s.Merge == nil {
+		return merged
+	}
+	return s.Merge(merged, a, b)
+}
+
 // Helper function to create an overlap iterator from a slice of list.
 func (s *SpanOverlapAccumulator[E]) NewOlssSeq2FromOlssSlice(list *[]*OverlappingSpanSets[E]) iter.Seq2[int, *OverlappingSpanSets[E]] {
   return slices.All(*list)
@@ -114,6 +177,9 @@ func (s *SpanOverlapAccumulator[E]) NewOlssSeq2FromSbChan(c <-chan SpanBoundry[E
 			}
 			span, ok = <-c
 		}
+		if !ok {
+			sa.Flush()
+		}
 	}
 	return func(yeild func(int, *OverlappingSpanSets[E]) bool) {
 		// no chan??? stop here
@@ -130,6 +196,9 @@ func (s *SpanOverlapAccumulator[E]) NewOlssSeq2FromSbChan(c <-chan SpanBoundry[E
 				if !yeild(id, current) {
 					return
 				}
+				if sa.Next != nil {
+					continue
+				}
 				var span, ok = <-c
 				for ok {
 					if sa.SetNext(span) {
@@ -137,6 +206,9 @@ func (s *SpanOverlapAccumulator[E]) NewOlssSeq2FromSbChan(c <-chan SpanBoundry[E
 					}
 					span, ok = <-c
 				}
+				if !ok {
+					sa.Flush()
+				}
 			} else {
 				return
 			}
@@ -172,6 +244,9 @@ func (s *SpanOverlapAccumulator[E]) NewOlssSeq2FromSbSlice(list *[]SpanBoundry[E
 			}
 			pos++
 		}
+		if pos == end {
+			au.Flush()
+		}
 	}
 
 	return func(yeild func(int, *OverlappingSpanSets[E]) bool) {
@@ -187,6 +262,9 @@ func (s *SpanOverlapAccumulator[E]) NewOlssSeq2FromSbSlice(list *[]SpanBoundry[E
 				if !yeild(id, current) {
 					return
 				}
+				if au.Next != nil {
+					continue
+				}
 				for pos < end {
 					if au.SetNext((*list)[pos]) {
 						pos++
@@ -194,6 +272,9 @@ func (s *SpanOverlapAccumulator[E]) NewOlssSeq2FromSbSlice(list *[]SpanBoundry[E
 					}
 					pos++
 				}
+				if pos == end {
+					au.Flush()
+				}
 			} else {
 				return
 			}
@@ -214,11 +295,28 @@ func (s *SpanOverlapAccumulator[E]) NewCoaFromOlssChan(c <-chan *OverlappingSpan
 
 // Creates a new context aware context.Context aware SpanBoundry[E] accumulation instance.
 func (s *SpanOverlapAccumulator[E]) NewOlssChanStater() *OlssChanStater[E] {
-	ctx,cancle :=context.WithCancel(context.Background())
+	return s.NewOlssChanStaterContext(context.Background())
+}
+
+// Same as NewOlssChanStater, but the context of the instance is derived from parent.
+// Once parent is done, CanAccumulate, Push and Final return false, the channel is closed and
+// OlssChanStater.Err is set to the cause, see: OlssChanStater.Push.
+//
+// Example:
+//
+//  s := sa.NewOlssChanStaterContext(ctx)
+//  go func() {
+//    defer s.Final()
+//    for s.CanAccumulate(span) {
+//      // get your next SpanBoundry
+//    }
+//  }()
+func (s *SpanOverlapAccumulator[E]) NewOlssChanStaterContext(parent context.Context) *OlssChanStater[E] {
+	ctx, cancle := context.WithCancel(parent)
 	return &OlssChanStater[E]{
 		Stater: *s.NewSpanIterSeq2Stater(),
-		Chan: make(chan *OverlappingSpanSets[E]),
-		Ctx: ctx,
+		Chan:   make(chan *OverlappingSpanSets[E]),
+		Ctx:    ctx,
 		Cancel: cancle,
 	}
 }
@@ -0,0 +1,157 @@
+package st
+
+import (
+	"iter"
+	"slices"
+	"sort"
+)
+
+// Sorted set of disjoint spans, that can be changed in any order.
+// Spans that overlap are merged when added, and when Consolidate is true, adjacent spans are merged as well.
+// Removing a span splits the spans it overlaps with.  Instances are not safe for concurrent use.
+//
+// Example:
+//
+//	var set = u.NewSpanSet()
+//	set.Add(u.Ns(5, 9))
+//	set.Add(u.Ns(1, 6))
+//	set.Remove(u.Ns(3, 3))
+//	// set now contains: [1 -> 2], [4 -> 9]
+//	ac.AddColumn(u.NewCoaFromOlssSeq2(set.NewOlssSeq2()))
+type SpanSet[E any] struct {
+	Util  *SpanUtil[E]
+	spans []SpanBoundry[E]
+}
+
+// Creates an empty SpanSet.
+func (s *SpanUtil[E]) NewSpanSet() *SpanSet[E] {
+	return &SpanSet[E]{Util: s, spans: []SpanBoundry[E]{}}
+}
+
+// Returns the index of the first span where test returns true.
+func (s *SpanSet[E]) search(test func(span SpanBoundry[E]) bool) int {
+	return sort.Search(len(s.spans), func(i int) bool {
+		return test(s.spans[i])
+	})
+}
+
+// Adds span to the set, merging it with the spans it overlaps with.
+// The error is a *SpanError[E] when span fails Check, and the set is not changed.
+func (s *SpanSet[E]) Add(span SpanBoundry[E]) error {
+	var u = s.Util
+	if err := u.Check(span, nil); err != nil {
+		return err
+	}
+	var begin, end = u.beginOf(span), u.endOf(span)
+	// spans that end just before span can be merged with it
+	var first = s.search(func(x SpanBoundry[E]) bool {
+		var next, ok = u.after(u.endOf(x))
+		return !ok || u.cmpBoundry(next, begin) > -1
+	})
+	var last = len(s.spans)
+	if next, ok := u.after(end); ok {
+		last = s.search(func(x SpanBoundry[E]) bool {
+			return u.cmpBoundry(u.beginOf(x), next) > 0
+		})
+	}
+	var list = append(slices.Clone(s.spans[first:last]), span)
+	s.spans = slices.Replace(s.spans, first, last, *u.Normalize(&list)...)
+	return nil
+}
+
+// Removes every value in span from the set, splitting the spans it overlaps with.
+//
+// See SpanUtil.Subtract for details on when the error is not nil, the set is not changed when it is.
+func (s *SpanSet[E]) Remove(span SpanBoundry[E]) error {
+	var first, last = s.overlaps(span)
+	if first == last {
+		return nil
+	}
+	var list = slices.Clone(s.spans[first:last])
+	var res, err = s.Util.subtract(&list, &[]SpanBoundry[E]{span})
+	if err != nil {
+		return err
+	}
+	s.spans = slices.Replace(s.spans, first, last, *res...)
+	return nil
+}
+
+// Returns the range of indexes of the spans that overlap with span.
+func (s *SpanSet[E]) overlaps(span SpanBoundry[E]) (int, int) {
+	var u = s.Util
+	var begin, end = u.beginOf(span), u.endOf(span)
+	var first = s.search(func(x SpanBoundry[E]) bool {
+		return u.cmpBoundry(u.endOf(x), begin) > -1
+	})
+	var last = s.search(func(x SpanBoundry[E]) bool {
+		return u.cmpBoundry(u.beginOf(x), end) > 0
+	})
+	return first, max(first, last)
+}
+
+// Returns true when the set contains point.
+func (s *SpanSet[E]) Contains(point E) bool {
+	var i = s.search(func(x SpanBoundry[E]) bool {
+		return s.Util.cmpBoundry(s.Util.endOf(x), boundry[E]{value: point}) > -1
+	})
+	return i < len(s.spans) && s.Util.Contains(s.spans[i], point)
+}
+
+// Returns true when every value in span is in the set.
+// When Consolidate is false, span can be covered by more than one adjacent span.
+func (s *SpanSet[E]) Covers(span SpanBoundry[E]) bool {
+	var u = s.Util
+	var begin, end = u.beginOf(span), u.endOf(span)
+	var i = s.search(func(x SpanBoundry[E]) bool {
+		return u.cmpBoundry(u.endOf(x), begin) > -1
+	})
+	if i == len(s.spans) || u.cmpBoundry(u.beginOf(s.spans[i]), begin) > 0 {
+		return false
+	}
+	// the ends are also compared by the point after them, so both ways of writing an end compare the same
+	var limit, bounded = u.after(end)
+	for {
+		var current = u.endOf(s.spans[i])
+		var next, ok = u.after(current)
+		if !ok || u.cmpBoundry(current, end) > -1 || bounded && u.cmpBoundry(next, limit) > -1 {
+			return true
+		}
+		i++
+		if i == len(s.spans) || u.cmpBoundry(u.beginOf(s.spans[i]), next) != 0 {
+			return false
+		}
+	}
+}
+
+// Returns the number of disjoint spans in the set.
+func (s *SpanSet[E]) Len() int {
+	return len(s.spans)
+}
+
+// Returns a sorted copy of the spans in the set.
+func (s *SpanSet[E]) Spans() *[]SpanBoundry[E] {
+	var res = slices.Clone(s.spans)
+	return &res
+}
+
+// Creates an iterator of the spans in the set, in order.
+// The set must not be changed while iterating.
+func (s *SpanSet[E]) All() iter.Seq[SpanBoundry[E]] {
+	return slices.Values(s.spans)
+}
+
+// Creates an iterator of the spans in the set, as OverlappingSpanSets, that can be used as a column.
+// The set must not be changed while iterating.
+//
+// Example:
+//
+//	ac.AddColumn(u.NewCoaFromOlssSeq2(set.NewOlssSeq2()))
+func (s *SpanSet[E]) NewOlssSeq2() iter.Seq2[int, *OverlappingSpanSets[E]] {
+	return func(yeild func(int, *OverlappingSpanSets[E]) bool) {
+		for i, span := range s.spans {
+			if !yeild(i, &OverlappingSpanSets[E]{Span: span, SrcBegin: i, SrcEnd: i}) {
+				return
+			}
+		}
+	}
+}
@@ -1,7 +1,7 @@
 package st
 
 import (
-	"errors"
+	"context"
 	"iter"
 )
 
@@ -15,9 +15,24 @@ type SpanUtil[E any] struct {
 	Validate bool
 
 	// Next value function, should return the next E.
-	// The new E value must always be greater than the argument passed in
+	// The new E value must always be greater than the argument passed in.
+	// When there is no value after e, Next should return e, this ends iteration instead of overflowing.
 	Next func(e E) E
 
+	// Optional alternative to Next, the bool value is false when there is no value after e.
+	// When not nil, NextOk is used instead of Next.  See: NewSpanUtilWithNextOk.
+	NextOk func(e E) (E, bool)
+
+	// Optional previous value function, should return the E that comes before e.
+	// The new E value must always be less than the argument passed in.
+	// This function is required when spans need to be carved, see: Subtract, SymmetricDifference and Gaps.
+	// When Validate is true, Check verifies that Prev(Next(x)) is equal to x.
+	Prev func(e E) E
+
+	// Optional alternative to Prev, the bool value is false when there is no value before e.
+	// When not nil, PrevOk is used instead of Prev.  See: NewSpanUtilWithNextOk.
+	PrevOk func(e E) (E, bool)
+
 	// Flag denoting if overlaps that are adjacent should be consolidated.
 	// Example of when true: 1,2 and 2,3 consolidate to 1,3, when false they do not consolidate.
 	// Default is false.
@@ -27,24 +42,56 @@ type SpanUtil[E any] struct {
 	Sort bool
 	
 	SpanFactory func(begin,end E) SpanBoundry[E]
+
+	// Creates spans with an open or closed begin and end, see: Nb.
+	BoundedSpanFactory func(begin E, beginInclusive bool, end E, endInclusive bool) SpanBoundry[E]
+
+	// When not nil, called with every span created by FirstSpan, NextSpan and CreateOverlapSpan, along with the
+	// spans from the list that overlap with it.  The returned span is used in its place, see: MergeTags.
+	Tagger func(span SpanBoundry[E], sources *[]SpanBoundry[E]) SpanBoundry[E]
+
+	// Default merge hook for objects created by this instance, see: SpanOverlapAccumulator.Merge.
+	Merge func(merged, a, b SpanBoundry[E]) SpanBoundry[E]
+
+	// Sentinel values and the unwrapped Cmp function, see: SetInfinity.
+	negInf  *E
+	posInf  *E
+	baseCmp func(a, b E) int
 }
 
 // This method is used to verify the sanity of the next and current value.
-// The comparison operation is performed in 2 stages:
+// The comparison operation is performed in 3 stages:
 // 1. next.GetBegin() must be less than or equal to next.GetEnd().
-// 2. When the current value is not nil, then next must come after current.
-// Returns nil when checks pass, the error is not nil when checks fail.
+// 2. When both Prev and Next are not nil, then Prev(Next(x)) must be equal to x for both the begin and end values of next.
+// 3. When the current value is not nil, then next must come after current.
+// Returns nil when checks pass, the error is a *SpanError[E] when checks fail.
 func (s *SpanUtil[E]) Check(next, current SpanBoundry[E]) error {
 
-	if s.Cmp(next.GetBegin(), next.GetEnd()) > 0 {
-		return errors.New("GetBegin must be less than or equal to GetEnd")
+	if s.cmpBoundry(s.beginOf(next), s.endOf(next)) > 0 {
+		return NewSpanError(ErrBeginAfterEnd, next, current)
+	}
+
+	if s.hasPrev() && s.hasNext() {
+		for _, x := range []E{next.GetBegin(), next.GetEnd()} {
+			if s.IsNegInf(x) || s.IsPosInf(x) {
+				continue
+			}
+			var after, ok = s.next(x)
+			if !ok {
+				// x is the largest value
+				continue
+			}
+			if prev, ok := s.prev(after); !ok || s.Cmp(prev, x) != 0 {
+				return NewSpanError(ErrPrevNotNext, next, current)
+			}
+		}
 	}
 
 	if current != nil {
 
 		if s.Compare(current, next) > 0 {
 
-			return errors.New("SpanBoundry out of sequence")
+			return NewSpanError(ErrOutOfSequence, next, current)
 		}
 	}
 	return nil
@@ -68,41 +115,118 @@ func NewSpanUtil[E any](cmp func(a, b E) int, next func(e E) E) *SpanUtil[E] {
 		Validate: true,
 		Sort:true,
 		SpanFactory: func(a,b E) SpanBoundry[E] { return &Span[E]{Begin: a, End: b} },
+		BoundedSpanFactory: func(a E, ai bool, b E, bi bool) SpanBoundry[E] {
+			return &BoundedSpan[E]{Begin: a, End: b, OpenBegin: !ai, OpenEnd: !bi}
+		},
+	}
+}
+
+// Creates an instance of *SpanUtil[E] with both a Next and a Prev function.
+// The prev function must return the value that comes before its argument, so that prev(next(x)) is equal to x.
+// The Prev function is required by operations that carve spans, such as: Subtract, SymmetricDifference and Gaps.
+func NewSpanUtilWithPrev[E any](cmp func(a, b E) int, next func(e E) E, prev func(e E) E) *SpanUtil[E] {
+	var s = NewSpanUtil(cmp, next)
+	s.Prev = prev
+	return s
+}
+
+// Creates an instance of *SpanUtil[E] where next and prev return false when there is no value after or before e.
+// This is the overflow safe way to define the Next and Prev functions, iteration ends cleanly at the last value instead
+// of wrapping around.  The prev function can be nil.
+//
+// Example of a SpanUtil[int] with a maximum value of 100:
+//
+//	var u = st.NewSpanUtilWithNextOk(
+//	  cmp.Compare,
+//	  func(e int) (int, bool) { return e + 1, e < 100 },
+//	  func(e int) (int, bool) { return e - 1, true },
+//	)
+func NewSpanUtilWithNextOk[E any](cmp func(a, b E) int, next func(e E) (E, bool), prev func(e E) (E, bool)) *SpanUtil[E] {
+	var s = NewSpanUtil(cmp, func(e E) E {
+		if res, ok := next(e); ok {
+			return res
+		}
+		return e
+	})
+	s.NextOk = next
+	if prev != nil {
+		s.PrevOk = prev
+		s.Prev = func(e E) E {
+			if res, ok := prev(e); ok {
+				return res
+			}
+			return e
+		}
+	}
+	return s
+}
+
+// Returns the value after e, the bool value is false when there is no value after e.
+func (s *SpanUtil[E]) next(e E) (E, bool) {
+	if s.NextOk != nil {
+		return s.NextOk(e)
+	}
+	var res = s.Next(e)
+	return res, s.Cmp(res, e) > 0
+}
+
+// Returns the value before e, the bool value is false when there is no value before e.
+func (s *SpanUtil[E]) prev(e E) (E, bool) {
+	if s.PrevOk != nil {
+		return s.PrevOk(e)
 	}
+	var res = s.Prev(e)
+	return res, s.Cmp(res, e) < 0
+}
+
+// Returns true when either Next or NextOk is not nil.
+func (s *SpanUtil[E]) hasNext() bool {
+	return s.Next != nil || s.NextOk != nil
+}
+
+// Returns true when either Prev or PrevOk is not nil.
+func (s *SpanUtil[E]) hasPrev() bool {
+	return s.Prev != nil || s.PrevOk != nil
 }
 
 // This method is used to sort slice of spans in the accumulation order.
 // For more details see: [slices.SortFunc].
 //
 // [slices.SortFunc]: https://pkg.go.dev/slices#SortedFunc
+//
+// When the begin values are equal, an inclusive begin comes before an exclusive begin.
+// When the end values are equal, an inclusive end comes after an exclusive end.
 func (s *SpanUtil[E]) Compare(a, b SpanBoundry[E]) int {
-	var diff int = s.Cmp(a.GetBegin(), b.GetBegin())
+	var diff int = s.cmpBoundry(s.beginOf(a), s.beginOf(b))
 	if diff == 0 {
-		return s.Cmp(b.GetEnd(), a.GetEnd())
+		return s.cmpBoundry(s.endOf(b), s.endOf(a))
 	}
 	return diff
 }
 
 // Returns true if a contains b.
 func (s *SpanUtil[E]) Contains(a SpanBoundry[E], b E) bool {
-	return s.Cmp(a.GetBegin(), b) < 1 && s.Cmp(a.GetEnd(), b) > -1
+	var point = boundry[E]{value: b}
+	return s.cmpBoundry(s.beginOf(a), point) < 1 && s.cmpBoundry(s.endOf(a), point) > -1
 }
 
 // Returns true if a overlaps with b or if be overlaps with a.
 func (s *SpanUtil[E]) Overlap(a, b SpanBoundry[E]) bool {
-	return s.Contains(a, b.GetBegin()) || s.Contains(a, b.GetEnd()) || s.Contains(b, a.GetEnd()) || s.Contains(b, a.GetEnd())
+	return s.cmpBoundry(s.beginOf(a), s.endOf(b)) < 1 && s.cmpBoundry(s.beginOf(b), s.endOf(a)) < 1
 }
 
 // This method is used to determine the outer bounds of ranges a and b.
 // The first int represents comparing a.Begin to b.Begin and the second int represents comparing a.End to b.End.
 func (s *SpanUtil[E]) ContainedBy(a, b SpanBoundry[E]) (int, int) {
-	return s.Cmp(a.GetBegin(), b.GetBegin()), s.Cmp(a.GetEnd(), b.GetEnd())
+	return s.cmpBoundry(s.beginOf(a), s.beginOf(b)), s.cmpBoundry(s.endOf(a), s.endOf(b))
 }
 
 // Creates a new span, error is nil unless a is greater than b.
+// The error is ErrBeginAfterEnd, it replaces the "Value a is greater than value b" error text of older versions, so
+// use errors.Is instead of comparing the text.
 func (s *SpanUtil[E]) NewSpan(a, b E) (SpanBoundry[E], error) {
 	if s.Cmp(a, b) > 0 {
-		return nil, errors.New("Value a is greater than value b")
+		return nil, ErrBeginAfterEnd
 	}
 	return s.Ns(a, b), nil
 }
@@ -119,30 +243,56 @@ func (s *SpanUtil[E]) Ns(a, b E) SpanBoundry[E] {
 // The resulting span is referred to as the "initial span".
 // If there is a begin value in list, that overlaps with the smallest end value, then
 // the "initial span" begin value will also be set as the end value for the "initial span".
+// When that begin value comes from a BoundedSpanBoundry, the "initial span" ends just before it instead.
 func (s *SpanUtil[E]) FirstSpan(list *[]SpanBoundry[E]) (SpanBoundry[E], bool) {
-	if list == nil || len(*list) == 0 {
+	var begin, end, ok = s.firstBoundries(list)
+	if !ok {
 		return nil, false
 	}
-	var span = s.Ns((*list)[0].GetBegin(),(*list)[0].GetEnd())
-	
+	return s.tag(s.fromBoundries(begin, end), list), true
+}
+
+// Same as FirstSpan, but returns the begin and end points, instead of creating a span.
+func (s *SpanUtil[E]) firstBoundries(list *[]SpanBoundry[E]) (boundry[E], boundry[E], bool) {
+	if list == nil || len(*list) == 0 {
+		return boundry[E]{}, boundry[E]{}, false
+	}
+	var begin = s.beginOf((*list)[0])
+	var end = s.endOf((*list)[0])
+
 	var last = len(*list)
-	var Cmp = s.Cmp
 	for i := 1; i < last; i++ {
 		var check = (*list)[i]
-		if Cmp(check.GetBegin(), span.GetEnd()) == -1 {
-			span= s.Ns(check.GetBegin(),span.GetEnd())
+		if b := s.beginOf(check); s.cmpBoundry(b, begin) == -1 {
+			begin = b
 		}
-		if Cmp(check.GetEnd(), span.GetEnd()) == -1 {
-			span= s.Ns(span.GetBegin(),check.GetEnd())
+		if e := s.endOf(check); s.cmpBoundry(e, end) == -1 {
+			end = e
 		}
 	}
+	// the first segment stops at the smallest begin value that comes after begin
+	var next boundry[E]
+	var found bool
 	for _, check := range *list {
-		if Cmp(check.GetBegin(), span.GetBegin()) > 0 && Cmp(span.GetEnd(), check.GetBegin()) > -1 {
-			span= s.Ns(span.GetBegin(),span.GetBegin())
-			return span, true
+		var b = s.beginOf(check)
+		if s.cmpBoundry(b, begin) > 0 && s.cmpBoundry(end, b) > -1 && (!found || s.cmpBoundry(b, next) < 0) {
+			next, found = b, true
 		}
 	}
-	return span, true
+	if found {
+		return begin, s.cut(next, begin), true
+	}
+	return begin, end, true
+}
+
+// Returns the end point of a span that stops at the begin point b.
+// Spans that are not a BoundedSpanBoundry stop at the value of fallback, otherwise they stop just before b.
+func (s *SpanUtil[E]) cut(b, fallback boundry[E]) boundry[E] {
+	if !b.bounded && b.offset == 0 {
+		return boundry[E]{value: fallback.value}
+	}
+	var res, _ = s.before(b)
+	return res
 }
 
 // Factory interface for the creation of SpanOverlapAccumulator[E].
@@ -155,6 +305,7 @@ func (s *SpanUtil[E]) NewSpanOverlapAccumulator() *SpanOverlapAccumulator[E] {
 		Pos:         -1,
 		Consolidate: s.Consolidate,
 		Sort: s.Sort,
+		Merge: s.Merge,
 	}
 }
 
@@ -218,26 +369,19 @@ func (s *SpanUtil[E]) CreateOverlapSpan(list *[]SpanBoundry[E]) (SpanBoundry[E],
 	if list == nil || len(*list) == 0 {
 		return nil, false
 	}
-	var begin *E
-	var end *E
-	var Cmp = s.Cmp
-	var res SpanBoundry[E];
-	for _, span := range *list {
-		if begin == nil {
-			begin = s.GetP(span.GetBegin())
-			end = s.GetP(span.GetEnd())
-			continue
-		}
-
-		if Cmp(span.GetBegin(), *begin) > 0 {
-			begin = s.GetP(span.GetBegin())
+	var begin = s.beginOf((*list)[0])
+	var end = s.endOf((*list)[0])
+	var res SpanBoundry[E]
+	for _, span := range (*list)[1:] {
+		if b := s.beginOf(span); s.cmpBoundry(b, begin) > 0 {
+			begin = b
 		}
-		if Cmp(*end, span.GetEnd()) > 0 {
-			end = s.GetP(span.GetEnd())
+		if e := s.endOf(span); s.cmpBoundry(end, e) > 0 {
+			end = e
 		}
 	}
-	if Cmp(*begin, *end) < 1 {
-		res = s.Ns(*begin, *end)
+	if s.cmpBoundry(begin, end) < 1 {
+		res = s.tag(s.fromBoundries(begin, end), list)
 	}
 	return res, true
 }
@@ -247,50 +391,73 @@ func (s *SpanUtil[E]) CreateOverlapSpan(list *[]SpanBoundry[E]) (SpanBoundry[E],
 //
 // How the span is generated:
 //
-// The begin is generated by calling the Next method.
+// The begin is generated by calling the Next method, when the end of start is exclusive, then
+// the end value of start is used as an inclusive begin instead.  When the end of start is inclusive
+// and Next is nil, then the end value of start is used as an exclusive begin.
 //
 // The end value is found via the following process:
 //   - Find the smallest end greater than equal to the value generated by Next
 //   - If a span begin value is greater than the Next value and less than all other end
 //     values then it will be used as the new end value for the initial span.
-//   - Once the new begin and end values are found, a call to CreateOverlapSpan is made,
-//     with our "initial span" and "list" to create our new span.
+//     When the span is a BoundedSpanBoundry, the new end value stops just before its begin value.
+//   - When no span contains the Next value, the result is a gap that stops just before the next begin value,
+//     when that span is a BoundedSpanBoundry or Prev is set.  Otherwise the gap stops at the next begin value.
 func (s *SpanUtil[E]) NextSpan(start SpanBoundry[E], list *[]SpanBoundry[E]) (SpanBoundry[E], bool) {
-	var min = s.Next(start.GetEnd())
-	var end *E
-	var res SpanBoundry[E]
-	var Cmp = s.Cmp
-	var ok = false
-	for _, span := range *list {
-		var cmp = Cmp(span.GetEnd(), min)
-		if end == nil {
-			if cmp > -1 {
-				end = s.GetP(span.GetEnd())
-				cmp = Cmp(span.GetBegin(), min)
-				if cmp > 0 && Cmp(span.GetBegin(), *end) < 0 {
-					end = s.GetP(span.GetBegin())
-				}
+	var begin, end, ok = s.nextBoundries(start, list)
+	if !ok {
+		return nil, false
+	}
+	return s.tag(s.fromBoundries(begin, end), list), true
+}
 
-			}
+// Same as NextSpan, but returns the begin and end points, instead of creating a span.
+func (s *SpanUtil[E]) nextBoundries(start SpanBoundry[E], list *[]SpanBoundry[E]) (boundry[E], boundry[E], bool) {
+	var min, ok = s.after(s.endOf(start))
+	if !ok {
+		return min, min, false
+	}
+	return s.boundriesFrom(min, list)
+}
+
+// Same as nextBoundries, but the segment begins at min.
+func (s *SpanUtil[E]) boundriesFrom(min boundry[E], list *[]SpanBoundry[E]) (boundry[E], boundry[E], bool) {
+	var end, first boundry[E]
+	var found, covered, gap bool
+	for _, span := range *list {
+		var e = s.endOf(span)
+		if s.cmpBoundry(e, min) > -1 && (!found || s.cmpBoundry(end, e) > 0) {
+			end, found = e, true
+		}
+		if !found {
 			continue
-		} else if cmp > -1 && Cmp(*end, span.GetEnd()) > 0 {
-			end = s.GetP(span.GetEnd())
 		}
-		cmp = Cmp(span.GetBegin(), min)
-		if cmp > 0 && Cmp(span.GetBegin(), *end) < 0 {
-			end = s.GetP(span.GetBegin())
+		var b = s.beginOf(span)
+		if s.cmpBoundry(b, min) > 0 {
+			var c = s.cut(b, b)
+			if s.cmpBoundry(c, end) < 0 {
+				end = c
+			}
+			if !gap || s.cmpBoundry(b, first) < 0 {
+				first, gap = b, true
+			}
+		} else if s.cmpBoundry(e, min) > -1 {
+			covered = true
 		}
 	}
-	if end != nil {
-		//var tmp = &Span[E]{Begin: min, End: *end}
-		var tmp = s.Ns(min, *end)
-		var ol = []SpanBoundry[E]{}
-		copy(ol, *list)
-		ol = append(ol, tmp)
-		res, ok = s.CreateOverlapSpan(&ol)
+	if gap && !covered {
+		// no span contains min, so the segment is a gap that stops just before the next span begins
+		end = s.gapEnd(first, end)
 	}
+	return min, end, found
+}
 
-	return res, ok
+// Returns the end point of a gap that stops just before the begin point b.
+// When there is no such point, see: before, fallback is returned, so closed spans keep their gaps without Prev.
+func (s *SpanUtil[E]) gapEnd(b, fallback boundry[E]) boundry[E] {
+	if res, err := s.before(b); err == nil {
+		return res
+	}
+	return fallback
 }
 
 // Creates a channel iterator for channel of OverlappingSpanSets.
@@ -314,3 +481,28 @@ func (s *SpanUtil[E]) NewOlssSeq2FromOlssChan(c <-chan *OverlappingSpanSets[E])
 
 	}
 }
+
+// Creates a channel iterator for the channel of sa, that stops once the context of sa is done.
+// The last OverlappingSpanSets has its Err set to the cause, when the context is done, see: OlssChanStater.Err.
+func (s *SpanUtil[E]) olssSeq2FromOlssChanStater(sa *OlssChanStater[E]) iter.Seq2[int, *OverlappingSpanSets[E]] {
+	return func(yeild func(int, *OverlappingSpanSets[E]) bool) {
+		for i := 0; ; i++ {
+			select {
+			case <-sa.Ctx.Done():
+				yeild(i, &OverlappingSpanSets[E]{Err: context.Cause(sa.Ctx), SrcBegin: -1, SrcEnd: -1})
+				return
+			case ol, ok := <-sa.Chan:
+				if !ok {
+					// the go routine closes the channel when the context is done, that is not the end of the data
+					if sa.Err != nil {
+						yeild(i, &OverlappingSpanSets[E]{Err: sa.Err, SrcBegin: -1, SrcEnd: -1})
+					}
+					return
+				}
+				if !yeild(i, ol) {
+					return
+				}
+			}
+		}
+	}
+}
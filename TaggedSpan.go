@@ -0,0 +1,140 @@
+package st
+
+// Extends SpanBoundry[E] with an attached payload of type V.
+type ValueSpanBoundry[E, V any] interface {
+	SpanBoundry[E]
+
+	// Returns the payload attached to the span.
+	GetValue() V
+}
+
+// Representation of a SpanBoundry[E] that carries a payload.
+// The Begin and End values, along with their inclusivity, come from the wrapped SpanBoundry.
+type TaggedSpan[E, V any] struct {
+	SpanBoundry[E]
+	// The payload attached to the span.
+	Value V
+}
+
+// Creates a new *TaggedSpan[E, V], that attaches value to span.
+//
+// Example:
+//
+//	var span = st.NewTaggedSpan(u.Ns(1, 2), "price change")
+func NewTaggedSpan[E, V any](span SpanBoundry[E], value V) *TaggedSpan[E, V] {
+	return &TaggedSpan[E, V]{SpanBoundry: span, Value: value}
+}
+
+// Returns the payload.
+func (s *TaggedSpan[E, V]) GetValue() V {
+	return s.Value
+}
+
+// Returns the wrapped SpanBoundry.
+func (s *TaggedSpan[E, V]) GetSpan() SpanBoundry[E] {
+	return s.SpanBoundry
+}
+
+// Returns the payload attached to span, the bool value is false when span does not carry a V.
+// Wrappers such as OvelapSources, OverlappingSpanSets and ColumnOverlapAccumulator are unwrapped.
+func ValueOf[E, V any](span SpanBoundry[E]) (V, bool) {
+	for span != nil {
+		if tagged, ok := span.(ValueSpanBoundry[E, V]); ok {
+			return tagged.GetValue(), true
+		}
+		var getter, ok = span.(spanGetter[E])
+		if !ok {
+			break
+		}
+		var next = getter.GetSpan()
+		if next == span {
+			break
+		}
+		span = next
+	}
+	var zero V
+	return zero, false
+}
+
+// Returns the payloads of the sources that carry a V, in source order.
+func Values[E, V any](sources *[]*OvelapSources[E]) *[]V {
+	var res = &[]V{}
+	if sources == nil {
+		return res
+	}
+	for _, src := range *sources {
+		if value, ok := ValueOf[E, V](src); ok {
+			*res = append(*res, value)
+		}
+	}
+	return res
+}
+
+// Creates a Tagger function for SpanUtil, that attaches the payloads of the sources to synthesized spans.
+// The payloads are folded with merge, in source order.  When no source carries a V, the span is returned as is.
+//
+// Example:
+//
+//	u.Tagger = st.MergeTags[int](func(a, b float64) float64 { return a + b })
+func MergeTags[E, V any](merge func(a, b V) V) func(span SpanBoundry[E], sources *[]SpanBoundry[E]) SpanBoundry[E] {
+	return func(span SpanBoundry[E], sources *[]SpanBoundry[E]) SpanBoundry[E] {
+		var res V
+		var found = false
+		for _, src := range *sources {
+			var value, ok = ValueOf[E, V](src)
+			if !ok {
+				continue
+			}
+			if found {
+				res = merge(res, value)
+			} else {
+				res = value
+				found = true
+			}
+		}
+		if !found {
+			return span
+		}
+		return NewTaggedSpan(span, res)
+	}
+}
+
+// Creates a Merge function for SpanOverlapAccumulator, that combines the payloads of merged spans with merge.
+// When only one of the spans carries a V, its payload is kept as is.
+//
+// Example, keeping the largest value:
+//
+//	ac.Merge = st.MergeValues[int](func(a, b float64) float64 { return max(a, b) })
+func MergeValues[E, V any](merge func(a, b V) V) func(merged, a, b SpanBoundry[E]) SpanBoundry[E] {
+	return func(merged, a, b SpanBoundry[E]) SpanBoundry[E] {
+		if tagged, ok := merged.(*TaggedSpan[E, V]); ok {
+			merged = tagged.SpanBoundry
+		}
+		var x, xOk = ValueOf[E, V](a)
+		var y, yOk = ValueOf[E, V](b)
+		if xOk && yOk {
+			return NewTaggedSpan(merged, merge(x, y))
+		}
+		if xOk {
+			return NewTaggedSpan(merged, x)
+		}
+		if yOk {
+			return NewTaggedSpan(merged, y)
+		}
+		return merged
+	}
+}
+
+// Passes span and the spans in list that overlap with it to the Tagger function, when set.
+func (s *SpanUtil[E]) tag(span SpanBoundry[E], list *[]SpanBoundry[E]) SpanBoundry[E] {
+	if s.Tagger == nil || span == nil {
+		return span
+	}
+	var sources = &[]SpanBoundry[E]{}
+	for _, src := range *list {
+		if s.Overlap(span, src) {
+			*sources = append(*sources, src)
+		}
+	}
+	return s.Tagger(span, sources)
+}
@@ -0,0 +1,103 @@
+package st
+
+import (
+	"cmp"
+	"math"
+	"net/netip"
+	"time"
+)
+
+// Constraint for all of the built in integer types.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Constraint for all of the built in integer and floating point types.
+type Number interface {
+	Integer | ~float32 | ~float64
+}
+
+// Creates an instance of *SpanUtil[T] for any integer type, with both a Next and a Prev function.
+// There is no value after the maximum value of T, and no value before the minimum value of T.
+func NewIntSpanUtil[T Integer]() *SpanUtil[T] {
+	return NewSpanUtilWithNextOk(
+		cmp.Compare[T],
+		func(e T) (T, bool) { return e + 1, e+1 > e },
+		func(e T) (T, bool) { return e - 1, e-1 < e },
+	)
+}
+
+// Creates an instance of *SpanUtil[time.Time], where Next and Prev step by resolution.
+// When resolution is less than or equal to 0, both Next and Prev are nil, and spans should be
+// half open, see: HalfOpen.
+func NewTimeSpanUtil(resolution time.Duration) *SpanUtil[time.Time] {
+	var compare = func(a, b time.Time) int { return a.Compare(b) }
+	if resolution <= 0 {
+		return NewSpanUtil(compare, nil)
+	}
+	return NewSpanUtilWithNextOk(
+		compare,
+		func(e time.Time) (time.Time, bool) {
+			var next = e.Add(resolution)
+			return next, next.After(e)
+		},
+		func(e time.Time) (time.Time, bool) {
+			var prev = e.Add(-resolution)
+			return prev, prev.Before(e)
+		},
+	)
+}
+
+// Creates an instance of *SpanUtil[time.Time] that only compares the year, month and day of each value.
+// Next and Prev step by one day, and always return midnight in the location of their argument.
+func NewDateSpanUtil() *SpanUtil[time.Time] {
+	return NewSpanUtilWithPrev(
+		func(a, b time.Time) int {
+			var ay, am, ad = a.Date()
+			var by, bm, bd = b.Date()
+			if ay != by {
+				return cmp.Compare(ay, by)
+			}
+			if am != bm {
+				return cmp.Compare(am, bm)
+			}
+			return cmp.Compare(ad, bd)
+		},
+		func(e time.Time) time.Time {
+			var y, m, d = e.Date()
+			return time.Date(y, m, d+1, 0, 0, 0, 0, e.Location())
+		},
+		func(e time.Time) time.Time {
+			var y, m, d = e.Date()
+			return time.Date(y, m, d-1, 0, 0, 0, 0, e.Location())
+		},
+	)
+}
+
+// Creates an instance of *SpanUtil[netip.Addr].
+// There is no value after the last address, and no value before the first address.
+func NewAddrSpanUtil() *SpanUtil[netip.Addr] {
+	return NewSpanUtilWithNextOk(
+		func(a, b netip.Addr) int { return a.Compare(b) },
+		func(e netip.Addr) (netip.Addr, bool) {
+			var next = e.Next()
+			return next, next.IsValid()
+		},
+		func(e netip.Addr) (netip.Addr, bool) {
+			var prev = e.Prev()
+			return prev, prev.IsValid()
+		},
+	)
+}
+
+// Creates an instance of *SpanUtil[float64], where Next and Prev return the next representable
+// float64 value, see: [math.Nextafter].  There is no value after +Inf, and no value before -Inf.
+//
+// [math.Nextafter]: https://pkg.go.dev/math#Nextafter
+func NewFloatSpanUtil() *SpanUtil[float64] {
+	return NewSpanUtilWithNextOk(
+		cmp.Compare[float64],
+		func(e float64) (float64, bool) { return math.Nextafter(e, math.Inf(1)), !math.IsInf(e, 1) },
+		func(e float64) (float64, bool) { return math.Nextafter(e, math.Inf(-1)), !math.IsInf(e, -1) },
+	)
+}
@@ -0,0 +1,95 @@
+package st
+
+// Sets the sentinel values used to represent -infinity and +infinity.
+//
+// The Cmp function is wrapped so that neg is always less than any other value, and pos is always greater
+// than any other value, regardless of how the original Cmp function would order them.
+// This allows for unbounded spans of any data type, including strings and custom types, where there is no
+// natural minimum or maximum value.  The Next function is never called on pos, so spans that end at +infinity
+// terminate FirstSpan, NextSpan, Accumulate and ColumnSets iteration without overflow.
+//
+// Example:
+//
+//	var u = st.NewSpanUtil(strings.Compare, func(e string) string { return e + "\x00" })
+//	u.SetInfinity("-inf", "+inf")
+//	// everything from "m" onward
+//	var span = u.NsFrom("m")
+func (s *SpanUtil[E]) SetInfinity(neg, pos E) {
+	var base = s.Cmp
+	if s.negInf != nil {
+		base = s.baseCmp
+	}
+	s.baseCmp = base
+	s.negInf = &neg
+	s.posInf = &pos
+	s.Cmp = func(a, b E) int {
+		var x, y = s.infinity(a), s.infinity(b)
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+		if x != 0 {
+			return 0
+		}
+		return base(a, b)
+	}
+}
+
+// Returns -1 when e is the -infinity sentinel, 1 when e is the +infinity sentinel, otherwise 0.
+func (s *SpanUtil[E]) infinity(e E) int {
+	if s.negInf == nil {
+		return 0
+	}
+	if s.baseCmp(e, *s.negInf) == 0 {
+		return -1
+	}
+	if s.baseCmp(e, *s.posInf) == 0 {
+		return 1
+	}
+	return 0
+}
+
+// Returns true when e is the -infinity sentinel set by SetInfinity.
+func (s *SpanUtil[E]) IsNegInf(e E) bool {
+	return s.infinity(e) < 0
+}
+
+// Returns true when e is the +infinity sentinel set by SetInfinity.
+func (s *SpanUtil[E]) IsPosInf(e E) bool {
+	return s.infinity(e) > 0
+}
+
+// Returns the -infinity sentinel, the bool value is false when SetInfinity has not been called.
+func (s *SpanUtil[E]) NegInf() (E, bool) {
+	if s.negInf == nil {
+		var zero E
+		return zero, false
+	}
+	return *s.negInf, true
+}
+
+// Returns the +infinity sentinel, the bool value is false when SetInfinity has not been called.
+func (s *SpanUtil[E]) PosInf() (E, bool) {
+	if s.posInf == nil {
+		var zero E
+		return zero, false
+	}
+	return *s.posInf, true
+}
+
+// Creates a new SpanBoundry[E] from a to +infinity, SetInfinity must be called first.
+func (s *SpanUtil[E]) NsFrom(a E) SpanBoundry[E] {
+	return s.Ns(a, *s.posInf)
+}
+
+// Creates a new SpanBoundry[E] from -infinity to b, SetInfinity must be called first.
+func (s *SpanUtil[E]) NsUntil(b E) SpanBoundry[E] {
+	return s.Ns(*s.negInf, b)
+}
+
+// Creates a new SpanBoundry[E] from -infinity to +infinity, SetInfinity must be called first.
+func (s *SpanUtil[E]) NsAll() SpanBoundry[E] {
+	return s.Ns(*s.negInf, *s.posInf)
+}
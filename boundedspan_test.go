@@ -0,0 +1,231 @@
+package st
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+)
+
+var floatDriver = NewSpanUtil[float64](cmp.Compare, nil)
+
+func CommonBoundedResult(name string, span SpanBoundry[float64], begin float64, bi bool, end float64, ei bool, t *testing.T) {
+	if span == nil {
+		t.Errorf("%s: expected a span, got nil", name)
+		return
+	}
+	if span.GetBegin() != begin || span.GetEnd() != end ||
+		spanBeginInclusive(span) != bi || spanEndInclusive(span) != ei {
+		t.Errorf("%s: expected: %v,%v -> %v,%v got: %v,%v -> %v,%v", name,
+			begin, bi, end, ei,
+			span.GetBegin(), spanBeginInclusive(span), span.GetEnd(), spanEndInclusive(span),
+		)
+	}
+}
+
+func TestBoundedOverlap(t *testing.T) {
+	var u = floatDriver
+	var a = u.HalfOpen(1, 3)
+	var b = u.HalfOpen(3, 5)
+	if u.Overlap(a, b) || u.Overlap(b, a) {
+		t.Error("Half open spans that touch, must not overlap")
+	}
+	if u.Contains(a, 3) || !u.Contains(b, 3) || !u.Contains(a, 1) {
+		t.Error("Invalid Contains for half open spans")
+	}
+	if u.Overlap(a, u.Ns(3, 3)) || !u.Overlap(a, u.Ns(2, 3)) {
+		t.Error("Invalid overlap for closed span")
+	}
+	if u.Check(u.HalfOpen(3, 3), nil) == nil {
+		t.Error("Empty half open span should fail Check")
+	}
+	if u.Check(u.Nb(3, true, 3, true), nil) != nil {
+		t.Error("Closed span of a single value should pass Check")
+	}
+	var list = []SpanBoundry[float64]{
+		u.Nb(1, false, 3, true),
+		u.Nb(1, true, 3, false),
+		u.Nb(1, true, 3, true),
+	}
+	slices.SortFunc(list, u.Compare)
+	CommonBoundedResult("Sort 0", list[0], 1, true, 3, true, t)
+	CommonBoundedResult("Sort 1", list[1], 1, true, 3, false, t)
+	CommonBoundedResult("Sort 2", list[2], 1, false, 3, true, t)
+}
+
+func TestBoundedAccumulate(t *testing.T) {
+	var u = NewSpanUtil[float64](cmp.Compare, nil)
+	u.Consolidate = true
+	var ac = u.NewSpanOverlapAccumulator()
+	var first, _ = ac.Accumulate(u.HalfOpen(1, 3))
+	var next, _ = ac.Accumulate(u.HalfOpen(3, 5))
+	if first != next {
+		t.Error("Adjacent half open spans should consolidate")
+	}
+	CommonBoundedResult("Consolidate", next.Span, 1, true, 5, false, t)
+	next, _ = ac.Accumulate(u.Nb(5, false, 6, true))
+	if first == next {
+		t.Error("Spans with a gap of a single value, should not consolidate")
+	}
+	next, _ = ac.Accumulate(u.Ns(5.5, 7))
+	CommonBoundedResult("Widen", next.Span, 5, false, 7, true, t)
+	if next.BeginInclusive() || !next.EndInclusive() {
+		t.Error("Invalid inclusivity")
+	}
+}
+
+func TestBoundedColumnSets(t *testing.T) {
+	var u = floatDriver
+	var cs = u.NewColumnSets()
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[float64]{u.HalfOpen(0, 10)})
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[float64]{u.HalfOpen(5, 15)})
+	var expected = []SpanBoundry[float64]{
+		u.HalfOpen(0, 5),
+		u.HalfOpen(5, 10),
+		u.HalfOpen(10, 15),
+	}
+	var counts = []int{1, 2, 1}
+	var total = 0
+	for id, res := range cs.Iter() {
+		total++
+		var span = expected[id]
+		CommonBoundedResult("Iter", res.GetSpan(), span.GetBegin(), true, span.GetEnd(), false, t)
+		if res.OverlapCount() != counts[id] {
+			t.Errorf("Expected %d columns, got %d", counts[id], res.OverlapCount())
+		}
+		// the bounded accessors are on the ColumnSets instance, not on the ColumnResults interface
+		if bounded, ok := res.(BoundedSpanBoundry[float64]); !ok || !bounded.BeginInclusive() || bounded.EndInclusive() {
+			t.Error("Invalid inclusivity of ColumnResults")
+		}
+		for _, col := range *res.GetColumns() {
+			if !col.BeginInclusive() || col.EndInclusive() {
+				t.Error("Invalid inclusivity of the column")
+			}
+		}
+	}
+	if total != 3 {
+		t.Errorf("Expected 3 results, got: %d", total)
+	}
+}
+
+func TestBoundedColumnSetsWithPrev(t *testing.T) {
+	var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	u.Prev = func(e int) int { return e - 1 }
+	var cs = u.NewColumnSets()
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{u.HalfOpen(2, 30)})
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{u.HalfOpen(6, 30)})
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{u.HalfOpen(3, 6), u.HalfOpen(8, 15)})
+	var ends = []int{2, 5, 7, 15, 30}
+	var counts = []int{1, 2, 2, 3, 2}
+	var total = 0
+	for id, res := range cs.Iter() {
+		total++
+		if id >= len(ends) {
+			t.Fatalf("Unexpected segment %d", id)
+		}
+		if res.GetEnd() != ends[id] || res.OverlapCount() != counts[id] {
+			t.Errorf("Expected end %d with %d columns, got end %d with %d columns", ends[id], counts[id], res.GetEnd(), res.OverlapCount())
+		}
+	}
+	if total != len(ends) {
+		t.Errorf("Expected %d results, got: %d", len(ends), total)
+	}
+}
+
+// A ColumnOverlap implemented outside of the package, that only has the methods of SpanBoundry.
+type plainColumnOverlap struct {
+	*Span[float64]
+}
+
+func (s plainColumnOverlap) GetSrcId() int                                 { return 0 }
+func (s plainColumnOverlap) GetEndId() int                                 { return 0 }
+func (s plainColumnOverlap) GetOverlaps() *[]*OverlappingSpanSets[float64] { return nil }
+func (s plainColumnOverlap) GetFirstSpan() (int, SpanBoundry[float64])     { return 0, s.Span }
+func (s plainColumnOverlap) GetLastSpan() (int, SpanBoundry[float64])      { return 0, s.Span }
+func (s plainColumnOverlap) GetSources() *[]*OvelapSources[float64]        { return nil }
+
+func TestBoundedPlainColumnOverlap(t *testing.T) {
+	var col = &CurrentColumn[float64]{ColumnOverlap: plainColumnOverlap{&Span[float64]{Begin: 1, End: 2}}}
+	if !col.BeginInclusive() || !col.EndInclusive() {
+		t.Error("Expected a ColumnOverlap without bounds to be inclusive")
+	}
+	col = &CurrentColumn[float64]{ColumnOverlap: floatDriver.NewSpanOverlapAccumulator().NewCoaFromSbSlice(&[]SpanBoundry[float64]{floatDriver.HalfOpen(1, 2)})}
+	if !col.BeginInclusive() || col.EndInclusive() {
+		t.Error("Expected the bounds of the column")
+	}
+}
+
+func TestBoundedFirstNextSpan(t *testing.T) {
+	var u = floatDriver
+	var list = &[]SpanBoundry[float64]{
+		u.Ns(0, 1),
+		u.Nb(1, false, 2, true),
+	}
+	var span, ok = u.FirstSpan(list)
+	if !ok {
+		t.Error("Expected a first span")
+		return
+	}
+	CommonBoundedResult("FirstSpan", span, 0, true, 1, true, t)
+	span, ok = u.NextSpan(span, list)
+	CommonBoundedResult("NextSpan", span, 1, false, 2, true, t)
+	_, ok = u.NextSpan(span, list)
+	if ok {
+		t.Error("Expected no more spans")
+	}
+}
+
+func TestBoundedFirstSpanSmallestBegin(t *testing.T) {
+	var u = floatDriver
+	// the first segment stops before the smallest begin value, not the first one found in list order
+	var list = &[]SpanBoundry[float64]{
+		u.Ns(0, 10),
+		u.Ns(5, 10),
+		u.Nb(3, false, 10, true),
+	}
+	var span, _ = u.FirstSpan(list)
+	CommonBoundedResult("FirstSpan", span, 0, true, 3, true, t)
+}
+
+func TestBoundedSetOperations(t *testing.T) {
+	var u = floatDriver
+	var a = &[]SpanBoundry[float64]{u.HalfOpen(0, 20)}
+	var b = &[]SpanBoundry[float64]{u.HalfOpen(2, 5), u.HalfOpen(8, 10)}
+	var res, err = u.Subtract(a, b)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+		return
+	}
+	if len(*res) != 3 {
+		t.Errorf("Expected 3 spans, got: %d", len(*res))
+		return
+	}
+	CommonBoundedResult("Subtract 0", (*res)[0], 0, true, 2, false, t)
+	CommonBoundedResult("Subtract 1", (*res)[1], 5, true, 8, false, t)
+	CommonBoundedResult("Subtract 2", (*res)[2], 10, true, 20, false, t)
+
+	var count = 0
+	for gap, err := range u.GapsFromSlice(u.Ns(0, 10), b) {
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			return
+		}
+		switch count {
+		case 0:
+			CommonBoundedResult("Gap 0", gap, 0, true, 2, false, t)
+		case 1:
+			CommonBoundedResult("Gap 1", gap, 5, true, 8, false, t)
+		case 2:
+			CommonBoundedResult("Gap 2", gap, 10, true, 10, true, t)
+		}
+		count++
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 gaps, got: %d", count)
+	}
+	var both = u.Intersect(a, &[]SpanBoundry[float64]{u.Ns(20, 21), u.Ns(19, 19)})
+	if len(*both) != 1 {
+		t.Errorf("Expected 1 span, got: %d", len(*both))
+		return
+	}
+	CommonBoundedResult("Intersect", (*both)[0], 19, true, 19, true, t)
+}
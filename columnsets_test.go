@@ -1,8 +1,15 @@
 package st
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"iter"
+	"runtime"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestEmptyColumnSet(t *testing.T) {
@@ -333,3 +340,288 @@ func TestColumnSetFactoryChanIterBreak(t *testing.T) {
 
 
 
+
+func TestNamedColumns(t *testing.T) {
+	var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	u.Sort = false
+	var cs = u.NewColumnSets()
+	defer cs.Close()
+	var a, _ = cs.AddNamedColumnFromSpanSlice("SetA", 10, &[]SpanBoundry[int]{u.Ns(1, 5)})
+	var b, _ = cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{u.Ns(2, 3)})
+	if id, ac := cs.AddNamedColumnFromSpanSlice("SetA", nil, &[]SpanBoundry[int]{u.Ns(2, 3)}); id != -1 || ac != nil {
+		t.Errorf("Names should be unique")
+	}
+	var c, _ = cs.AddNamedColumnFromSpanSlice("SetC", "meta", &[]SpanBoundry[int]{u.Ns(4, 9), u.Ns(2, 2)})
+	if a != 0 || b != 1 || c != 2 {
+		t.Errorf("Invalid column ids: %d, %d, %d", a, b, c)
+	}
+	if cs.ColumnIdOf("SetA") != 0 || cs.ColumnIdOf("SetC") != 2 || cs.ColumnIdOf("") != -1 || cs.ColumnIdOf("SetX") != -1 {
+		t.Errorf("Invalid name lookups")
+	}
+	if cs.ColumnName(0) != "SetA" || cs.ColumnName(1) != "" || cs.ColumnName(3) != "" {
+		t.Errorf("Invalid column names")
+	}
+	for _, res := range cs.Iter() {
+		for _, col := range *res.GetColumns() {
+			if col.Name != cs.ColumnName(col.ColumnId) {
+				t.Errorf("Expected name: %s, got: %s", cs.ColumnName(col.ColumnId), col.Name)
+			}
+			if col.ColumnId == 0 && col.Meta != 10 {
+				t.Errorf("Expected meta value 10, got: %v", col.Meta)
+			}
+		}
+	}
+	var spanErr *SpanError[int]
+	if !errors.As(cs.Err, &spanErr) || spanErr.ColumnName != "SetC" || cs.ErrCol != 2 {
+		t.Errorf("Expected an error from SetC, got: %v", cs.Err)
+		return
+	}
+	if !strings.Contains(spanErr.Error(), "ColumnName: SetC") {
+		t.Errorf("Expected the name in the error message: %s", spanErr.Error())
+	}
+}
+
+func TestNamedColumnSources(t *testing.T) {
+	var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	var cs = u.NewColumnSets()
+	defer cs.Close()
+	var a = cs.AddNamedColumnFromOverlappingSpanSets("SetA", 1, &[]*OverlappingSpanSets[int]{{Span: u.Ns(1, 2)}})
+	var s = u.NewSpanOverlapAccumulator().NewOlssChanStater()
+	var done = make(chan struct{})
+	go func() {
+		defer close(done)
+		defer s.Final()
+		for i := 0; s.CanAccumulate(u.Ns(i, i)); i++ {
+		}
+	}()
+	var b = cs.AddNamedColumnFromNewOlssChanStater("SetA", 2, s)
+	if a != 0 || b != -1 || cs.ColumnIdOf("SetA") != 0 {
+		t.Errorf("Invalid column ids: %d, %d", a, b)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Errorf("Expected the go routine of the duplicate column to stop")
+	}
+	var c = u.NewCoaFromOlssSeq2(slices.All([]*OverlappingSpanSets[int]{{Span: u.Ns(1, 2)}}))
+	if cs.AddNamedColumn("SetA", nil, c) != -1 || !c.Closed {
+		t.Errorf("Expected the duplicate column to be closed")
+	}
+	var d = cs.AddNamedColumnFromOverlappingSpanSets("SetD", 4, &[]*OverlappingSpanSets[int]{{Span: u.Ns(2, 3)}})
+	if d != 1 || cs.ColumnName(d) != "SetD" {
+		t.Errorf("Invalid column: %d", d)
+	}
+}
+
+func TestDynamicColumns(t *testing.T) {
+	var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	var cs = u.NewColumnSets()
+	defer cs.Close()
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{u.HalfOpen(1, 10), u.HalfOpen(12, 30)})
+	var closed = 0
+	var removed = -1
+	var res = [][3]int{}
+	for _, seg := range cs.Iter() {
+		res = append(res, [3]int{seg.GetBegin(), seg.GetEnd(), seg.OverlapCount()})
+		switch len(res) {
+		case 1:
+			var _, ac = cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{u.HalfOpen(0, 2), u.HalfOpen(5, 6), u.HalfOpen(8, 20)})
+			if ac.Pos != 2 {
+				t.Errorf("Expected the new column to catch up, got Pos: %d", ac.Pos)
+			}
+		case 2:
+			var col = u.NewColumnOverlapAccumulator(iter.Pull2(u.NewSpanOverlapAccumulator().NewOlssSeq2FromSbSlice(&[]SpanBoundry[int]{u.HalfOpen(13, 40)})))
+			col.AddOnClose(func() { closed++ })
+			removed = cs.AddColumn(col)
+		case 3:
+			if !cs.RemoveColumn(removed) || cs.RemoveColumn(removed) || cs.RemoveColumn(99) {
+				t.Errorf("Invalid RemoveColumn results")
+			}
+		}
+	}
+	var expected = [][3]int{{1, 10, 1}, {10, 12, 1}, {12, 13, 2}, {13, 20, 2}, {20, 30, 1}}
+	if len(res) != len(expected) {
+		t.Errorf("Expected: %v, got: %v", expected, res)
+		return
+	}
+	for i, r := range expected {
+		if res[i] != r {
+			t.Errorf("Expected: %v, got: %v", expected, res)
+		}
+	}
+	if closed != 1 {
+		t.Errorf("Expected OnClose to be called once, got: %d", closed)
+	}
+}
+
+// Starts a go routine that pushes the spans from begin onward until s stops accepting them.
+// The returned channel is closed once the go routine has stopped.
+func accumulateEndless(s *OlssChanStater[int], begin int) chan struct{} {
+	var stopped = make(chan struct{})
+	go func() {
+		defer close(stopped)
+		defer s.Final()
+		for i := begin; s.CanAccumulate(&Span[int]{Begin: i, End: i}); i++ {
+		}
+	}()
+	return stopped
+}
+
+// Adds a column fed by accumulateEndless, that runs until it is canceled.
+func addEndlessColumn(cs *ColumnSets[int], begin int) (*OlssChanStater[int], chan struct{}) {
+	var s = testDriver.NewSpanOverlapAccumulator().NewOlssChanStater()
+	var stopped = accumulateEndless(s, begin)
+	cs.AddColumnFromNewOlssChanStater(s)
+	return s, stopped
+}
+
+func waitFor(t *testing.T, stopped chan struct{}) {
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("The go routine was not stopped")
+	}
+}
+
+func TestIterContext(t *testing.T) {
+	var ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	var cs = testDriver.NewColumnSets()
+	defer cs.Close()
+	var _, stopped = addEndlessColumn(cs, 0)
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{&Span[int]{Begin: 5, End: 1000}})
+	var count = 0
+	for range cs.IterContext(ctx) {
+		count++
+		if count == 10 {
+			cancel()
+		}
+		if count > 1000 {
+			t.Fatal("The iterator did not stop")
+		}
+	}
+	if !errors.Is(cs.Err, context.Canceled) || cs.ErrCol != -1 {
+		t.Errorf("Expected context.Canceled, got: %v, column: %d", cs.Err, cs.ErrCol)
+	}
+	if count != 10 {
+		t.Errorf("Expected 10 segments, got: %d", count)
+	}
+	waitFor(t, stopped)
+}
+
+func TestIterContextDeadline(t *testing.T) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	var cs = testDriver.NewColumnSets()
+	defer cs.Close()
+	// pushes a single span, then waits without closing the channel
+	var s = testDriver.NewSpanOverlapAccumulator().NewOlssChanStater()
+	var stopped = make(chan struct{})
+	go func() {
+		defer close(stopped)
+		defer s.Final()
+		s.Push(&OverlappingSpanSets[int]{Span: &Span[int]{Begin: 1, End: 2}})
+		<-s.Ctx.Done()
+	}()
+	cs.AddColumnFromNewOlssChanStater(s)
+	var _, endless = addEndlessColumn(cs, 3)
+	// the first segment waits on the second span of s, until the deadline
+	for range cs.IterContext(ctx) {
+	}
+	if !errors.Is(cs.Err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got: %v", cs.Err)
+	}
+	waitFor(t, stopped)
+	waitFor(t, endless)
+}
+
+func TestIterContextDone(t *testing.T) {
+	var before = runtime.NumGoroutine()
+	var ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	var cs = testDriver.NewColumnSets()
+	for range 10 {
+		cs.AddColumnFromOverlappingSpanSets(MakeOverlapTestList())
+	}
+	var _, stopped = addEndlessColumn(cs, 0)
+	for range cs.IterContext(ctx) {
+		t.Fatal("Expected no segments")
+	}
+	if !errors.Is(cs.Err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got: %v", cs.Err)
+	}
+	waitFor(t, stopped)
+	// the columns are closed when the iterator stops, which ends the go routines of iter.Pull2
+	for i := 0; runtime.NumGoroutine() > before; i++ {
+		if i == 100 {
+			t.Fatalf("Expected %d go routines, got: %d", before, runtime.NumGoroutine())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Returns the accumulated sets for each column, so the benchmarks only measure ColumnSets.
+func benchColumns(u *SpanUtil[int], columns int) [][]*OverlappingSpanSets[int] {
+	var res = [][]*OverlappingSpanSets[int]{}
+	var spans = max(10, 10000/columns)
+	for c := range columns {
+		var list = &[]SpanBoundry[int]{}
+		// each column covers about a tenth of the range
+		var gap = 200000 / spans
+		var pos = (c * 37) % gap
+		for i := range spans {
+			*list = append(*list, u.HalfOpen(pos, pos+1+(gap/10+i+c)%(gap/5)))
+			pos += gap
+		}
+		var sets = []*OverlappingSpanSets[int]{}
+		for _, ol := range u.NewSpanOverlapAccumulator().NewOlssSeq2FromSbSlice(list) {
+			sets = append(sets, ol)
+		}
+		res = append(res, sets)
+	}
+	return res
+}
+
+func benchmarkColumnSets(b *testing.B, columns int, engine ColumnEngine) {
+	var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	var data = benchColumns(u, columns)
+	var segments, mallocs uint64
+	var before, after runtime.MemStats
+	b.ReportAllocs()
+	for b.Loop() {
+		var cs = u.NewColumnSets()
+		cs.Engine = engine
+		cs.ReuseBuffers = true
+		for _, sets := range data {
+			var i = 0
+			cs.AddColumn(u.NewColumnOverlapAccumulator(func() (int, *OverlappingSpanSets[int], bool) {
+				if i == len(sets) {
+					return i, nil, false
+				}
+				i++
+				return i - 1, sets[i-1], true
+			}, nil))
+		}
+		runtime.ReadMemStats(&before)
+		for _, res := range cs.Iter() {
+			segments++
+			for _, col := range *res.GetColumns() {
+				col.GetSrcId()
+			}
+		}
+		runtime.ReadMemStats(&after)
+		mallocs += after.Mallocs - before.Mallocs
+	}
+	b.ReportMetric(float64(mallocs)/float64(segments), "allocs/segment")
+}
+
+func BenchmarkColumnSets(b *testing.B) {
+	for _, columns := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("linear/%d", columns), func(b *testing.B) {
+			benchmarkColumnSets(b, columns, LinearEngine)
+		})
+		b.Run(fmt.Sprintf("heap/%d", columns), func(b *testing.B) {
+			benchmarkColumnSets(b, columns, HeapEngine)
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package st
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCheckErrors(t *testing.T) {
+	var err = testDriver.Check(testDriver.Ns(2, 1), nil)
+	if !errors.Is(err, ErrBeginAfterEnd) {
+		t.Errorf("Expected ErrBeginAfterEnd, got: %v", err)
+	}
+	var prev = testDriver.Ns(3, 4)
+	err = testDriver.Check(testDriver.Ns(1, 2), prev)
+	if !errors.Is(err, ErrOutOfSequence) {
+		t.Errorf("Expected ErrOutOfSequence, got: %v", err)
+	}
+	var spanErr *SpanError[int]
+	if !errors.As(err, &spanErr) {
+		t.Errorf("Expected a *SpanError, got: %v", err)
+		return
+	}
+	if spanErr.Previous != prev || spanErr.Span.GetBegin() != 1 || spanErr.Pos != -1 || spanErr.ColumnId != -1 {
+		t.Errorf("Invalid error details: %v", spanErr)
+	}
+	if _, err = testDriver.NewSpan(2, 1); !errors.Is(err, ErrBeginAfterEnd) {
+		t.Errorf("Expected ErrBeginAfterEnd, got: %v", err)
+	}
+	if _, err = testDriver.Subtract(&[]SpanBoundry[int]{testDriver.Ns(1, 3)}, &[]SpanBoundry[int]{testDriver.Ns(2, 2)}); !errors.Is(err, ErrPrevRequired) {
+		t.Errorf("Expected ErrPrevRequired, got: %v", err)
+	}
+}
+
+func TestColumnErrorCopy(t *testing.T) {
+	var src = NewSpanError(ErrOutOfSequence, testDriver.Ns(1, 2), nil)
+	var a, b = columnError[int](src, 1), columnError[int](src, 2)
+	a.ColumnName = "a"
+	if src.ColumnId != -1 || src.ColumnName != "" {
+		t.Errorf("Expected the shared error to be left alone, got: %v", src)
+	}
+	if a.ColumnId != 1 || b.ColumnId != 2 || b.ColumnName != "" || !errors.Is(a, ErrOutOfSequence) {
+		t.Errorf("Invalid column errors: %v, %v", a, b)
+	}
+}
+
+func TestColumnSetsSpanError(t *testing.T) {
+	var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	u.Sort = false
+	var cs = u.NewColumnSets()
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{u.Ns(1, 7)})
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{
+		u.Ns(1, 2),
+		u.Ns(13, 14),
+		u.Ns(6, 6),
+	})
+	for range cs.Iter() {
+	}
+	var spanErr *SpanError[int]
+	if !errors.As(cs.Err, &spanErr) || !errors.Is(cs.Err, ErrOutOfSequence) {
+		t.Errorf("Expected a *SpanError, got: %v", cs.Err)
+		return
+	}
+	if spanErr.ColumnId != 1 || cs.ErrCol != 1 || spanErr.Pos != 2 || spanErr.Span.GetBegin() != 6 || spanErr.Previous.GetBegin() != 13 {
+		t.Errorf("Invalid error details: %v", spanErr)
+	}
+	for _, part := range []string{"out of sequence", "ColumnId: 1", "Pos: 2", "Span: [6 -> 6]", "Previous: [13 -> 14]"} {
+		if !strings.Contains(spanErr.Error(), part) {
+			t.Errorf("Expected %s in: %s", part, spanErr.Error())
+		}
+	}
+
+	var force = errors.New("Force init error")
+	cs = u.NewColumnSets()
+	cs.AddColumnFromOverlappingSpanSets(&[]*OverlappingSpanSets[int]{{Err: force}})
+	for range cs.Iter() {
+	}
+	if !errors.Is(cs.Err, force) || !errors.As(cs.Err, &spanErr) || spanErr.ColumnId != 0 {
+		t.Errorf("Expected the wrapped error, got: %v", cs.Err)
+	}
+}
+
+func TestSkipOnError(t *testing.T) {
+	var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	u.Sort = false
+	var ac = u.NewSpanOverlapAccumulator()
+	ac.ErrorPolicy = SkipOnError
+	var skipped = []*SpanError[int]{}
+	ac.OnError = func(err *SpanError[int]) {
+		skipped = append(skipped, err)
+	}
+	var list = &[]SpanBoundry[int]{
+		u.Ns(2, 1),
+		u.Ns(1, 3),
+		u.Ns(0, 0),
+		u.Ns(2, 4),
+		u.Ns(7, 8),
+	}
+	var res = [][2]int{}
+	var srcs = [][]int{}
+	for _, ol := range ac.NewOlssSeq2FromSbSlice(list) {
+		res = append(res, [2]int{ol.GetBegin(), ol.GetEnd()})
+		var ids = []int{}
+		for _, src := range *ol.GetSources() {
+			ids = append(ids, src.SrcId)
+		}
+		srcs = append(srcs, ids)
+	}
+	if ac.Err != nil {
+		t.Errorf("Expected no error, got: %v", ac.Err)
+	}
+	if len(res) != 2 || res[0] != [2]int{1, 4} || res[1] != [2]int{7, 8} {
+		t.Errorf("Invalid results: %v", res)
+	}
+	if len(srcs) != 2 || len(srcs[0]) != 2 || srcs[0][0] != 1 || srcs[0][1] != 3 || srcs[1][0] != 4 {
+		t.Errorf("Invalid source ids: %v", srcs)
+	}
+	if len(skipped) != 2 || !errors.Is(skipped[0], ErrBeginAfterEnd) || skipped[0].Pos != 0 ||
+		!errors.Is(skipped[1], ErrOutOfSequence) || skipped[1].Pos != 2 {
+		t.Errorf("Invalid skipped spans: %v", skipped)
+	}
+}
+
+func TestColumnSetsSkipOnError(t *testing.T) {
+	var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	u.Sort = false
+	var cs = u.NewColumnSets()
+	cs.ErrorPolicy = SkipOnError
+	var skipped = []*SpanError[int]{}
+	cs.OnError = func(err *SpanError[int]) {
+		skipped = append(skipped, err)
+	}
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{u.Ns(1, 7)})
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{
+		u.Ns(1, 2),
+		u.Ns(13, 14),
+		u.Ns(6, 6),
+		u.Ns(20, 21),
+	})
+	cs.AddColumnFromOverlappingSpanSets(&[]*OverlappingSpanSets[int]{{Err: errors.New("Force init error")}})
+	var res = [][2]int{}
+	for _, col := range cs.Iter() {
+		res = append(res, [2]int{col.GetBegin(), col.GetEnd()})
+	}
+	if cs.Err != nil {
+		t.Errorf("Expected no error, got: %v", cs.Err)
+	}
+	var expected = [][2]int{{1, 2}, {3, 7}, {8, 13}, {14, 14}, {15, 20}, {21, 21}}
+	if len(res) != len(expected) {
+		t.Errorf("Invalid results: %v", res)
+		return
+	}
+	for i, span := range expected {
+		if res[i] != span {
+			t.Errorf("Invalid results: %v", res)
+		}
+	}
+	if len(skipped) != 2 || skipped[0].ColumnId != 2 || skipped[1].ColumnId != 1 || skipped[1].Pos != 2 {
+		t.Errorf("Invalid skipped errors: %v", skipped)
+	}
+}
+
+func TestColumnSetsSkipOnErrorRows(t *testing.T) {
+	for _, engine := range []ColumnEngine{LinearEngine, HeapEngine} {
+		var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+		var cs = u.NewColumnSets()
+		cs.Engine = engine
+		cs.ErrorPolicy = SkipOnError
+		var skipped = []*SpanError[int]{}
+		cs.OnError = func(err *SpanError[int]) {
+			skipped = append(skipped, err)
+		}
+		cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{u.Ns(1, 20)})
+		cs.AddColumnFromOverlappingSpanSets(&[]*OverlappingSpanSets[int]{
+			{Err: NewSpanError[int](ErrOutOfSequence, u.Ns(0, 0), nil), SrcBegin: -1, SrcEnd: -1},
+			{Span: u.Ns(2, 3), SrcBegin: 0, SrcEnd: 0},
+			{Err: NewSpanError[int](ErrOutOfSequence, u.Ns(1, 1), nil), SrcBegin: -1, SrcEnd: -1},
+			{Span: u.Ns(4, 6), SrcBegin: 1, SrcEnd: 1},
+		})
+		var res = [][3]int{}
+		for _, col := range cs.Iter() {
+			res = append(res, [3]int{col.GetBegin(), col.GetEnd(), col.OverlapCount()})
+		}
+		if cs.Err != nil {
+			t.Errorf("Expected no error, got: %v", cs.Err)
+		}
+		var expected = [][3]int{{1, 1, 1}, {2, 3, 2}, {4, 6, 2}, {7, 20, 1}}
+		if !slices.Equal(res, expected) {
+			t.Errorf("Engine: %d, Invalid results: %v", engine, res)
+		}
+		if len(skipped) != 2 || skipped[0].ColumnId != 1 || skipped[1].ColumnId != 1 ||
+			skipped[0].Span.GetBegin() != 0 || skipped[1].Span.GetBegin() != 1 {
+			t.Errorf("Engine: %d, Invalid skipped errors: %v", engine, skipped)
+		}
+	}
+}
@@ -112,3 +112,50 @@ func TestGetNextSpanAllColumnsSetB(t *testing.T) {
 
 	CommonNextSpan(src,expected,t)
 }
+
+func TestNextSpanNextOk(t *testing.T) {
+	var u = NewSpanUtilWithNextOk(
+		testDriver.Cmp,
+		func(e int) (int, bool) { return e + 1, e < 10 },
+		func(e int) (int, bool) { return e - 1, e > 0 },
+	)
+	if u.Next(10) != 10 || u.Prev(0) != 0 || u.Next(1) != 2 || u.Prev(1) != 0 {
+		t.Error("Next and Prev should stop at the bounds")
+	}
+	var src = &[]SpanBoundry[int]{u.Ns(5, 10)}
+	var span, ok = u.NextSpan(u.Ns(0, 10), src)
+	if ok {
+		t.Errorf("There should be no span after the max value, got: %v", span)
+	}
+	if u.Check(u.Ns(0, 10), nil) != nil {
+		t.Error("Check should pass at the bounds")
+	}
+
+	u.Consolidate = true
+	var ac = u.NewSpanOverlapAccumulator()
+	var first, _ = ac.Accumulate(u.Ns(8, 10))
+	var next, _ = ac.Accumulate(u.Ns(10, 10))
+	if first != next {
+		t.Error("Overlapping spans at the max value should consolidate")
+	}
+
+	var cs = u.NewColumnSets()
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{u.Ns(0, 10)})
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{u.Ns(4, 10)})
+	var count = 0
+	for range cs.Iter() {
+		count++
+		if count > 10 {
+			t.Error("Iteration did not stop at the max value")
+			return
+		}
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 spans, got: %d", count)
+	}
+
+	var res, err = u.Subtract(&[]SpanBoundry[int]{u.Ns(0, 10)}, &[]SpanBoundry[int]{u.Ns(0, 0)})
+	if err != nil || len(*res) != 1 || (*res)[0].GetBegin() != 1 {
+		t.Errorf("Invalid Subtract: %v, %v", res, err)
+	}
+}
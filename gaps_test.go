@@ -0,0 +1,70 @@
+package st
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func CommonGapResult(name string, seq func(func(SpanBoundry[int], error) bool), expected [][2]int, t *testing.T) {
+	var res = []SpanBoundry[int]{}
+	for gap, err := range seq {
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			return
+		}
+		res = append(res, gap)
+	}
+	CommonSetResult(name, &res, expected, t)
+}
+
+func TestGaps(t *testing.T) {
+	var list = &[]SpanBoundry[int]{
+		&Span[int]{Begin: 8, End: 9},
+		&Span[int]{Begin: 2, End: 3},
+		&Span[int]{Begin: 3, End: 5},
+		&Span[int]{Begin: 12, End: 20},
+	}
+	CommonGapResult("Gaps", setDriver.GapsFromSlice(setDriver.Ns(0, 15), list), [][2]int{{0, 1}, {6, 7}, {10, 11}}, t)
+	CommonGapResult("Gaps tail", setDriver.GapsFromSlice(setDriver.Ns(4, 25), list), [][2]int{{6, 7}, {10, 11}, {21, 25}}, t)
+	CommonGapResult("Gaps none", setDriver.GapsFromSlice(setDriver.Ns(13, 14), list), [][2]int{}, t)
+	CommonGapResult("Gaps empty", setDriver.GapsFromSlice(setDriver.Ns(13, 14), nil), [][2]int{{13, 14}}, t)
+
+	var ac = setDriver.NewSpanOverlapAccumulator()
+	CommonGapResult("Gaps Olss", setDriver.GapsFromOlssSeq2(setDriver.Ns(0, 15), ac.NewOlssSeq2FromSbSlice(list)), [][2]int{{0, 1}, {6, 7}, {10, 11}}, t)
+
+	// only values after a covered span can be found without a Prev function
+	CommonGapResult("Gaps no Prev", testDriver.GapsFromSlice(testDriver.Ns(2, 7), list), [][2]int{{6, 7}}, t)
+	for gap, err := range testDriver.GapsFromSlice(testDriver.Ns(0, 15), list) {
+		if err == nil {
+			t.Errorf("Expected an error, got: %v", gap)
+		}
+	}
+	for range setDriver.GapsFromSlice(setDriver.Ns(0, 15), list) {
+		break
+	}
+}
+
+func TestGapsOlssError(t *testing.T) {
+	var list = []*OverlappingSpanSets[int]{
+		{Span: setDriver.Ns(2, 3)},
+		{Span: setDriver.Ns(5, 5), Err: errors.New("Force error")},
+	}
+	var seq = setDriver.GapsFromOlssSeq2(setDriver.Ns(0, 10), slices.All(list))
+	// the error of the first run must not leak into the second one
+	for run := range 2 {
+		var count = 0
+		for gap, err := range seq {
+			count++
+			if count == 1 && (err != nil || gap.GetBegin() != 0 || gap.GetEnd() != 1) {
+				t.Errorf("Run %d: Expected gap 0->1, got: %v, %v", run, gap, err)
+			}
+			if count == 2 && err == nil {
+				t.Errorf("Run %d: Expected an error, got: %v", run, gap)
+			}
+		}
+		if count != 2 {
+			t.Errorf("Run %d: Expected 2 results, got: %d", run, count)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package st
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Returns a SpanUtil for the variant: 0 plain, 1 with Prev, 2 with infinity.
+func engineUtil(variant int) *SpanUtil[int] {
+	var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	switch variant {
+	case 1:
+		u.Prev = func(e int) int { return e - 1 }
+	case 2:
+		u.SetInfinity(math.MinInt, math.MaxInt)
+	}
+	return u
+}
+
+func randomSpans(r *rand.Rand, u *SpanUtil[int], spans int, bounded bool) *[]SpanBoundry[int] {
+	var list = &[]SpanBoundry[int]{}
+	var pos = r.Intn(20)
+	for range spans {
+		pos += r.Intn(8)
+		var end = pos + r.Intn(20)
+		var span SpanBoundry[int]
+		if bounded && r.Intn(2) == 0 {
+			span = u.Nb(pos, r.Intn(3) != 0, end+1, r.Intn(3) == 0)
+		} else {
+			span = u.Ns(pos, end)
+		}
+		if r.Intn(4) == 0 {
+			span = NewTaggedSpan(span, r.Intn(100))
+		}
+		*list = append(*list, span)
+	}
+	if _, ok := u.PosInf(); ok && r.Intn(4) == 0 {
+		*list = append(*list, u.NsFrom(pos+r.Intn(30)))
+	}
+	if r.Intn(10) == 0 && len(*list) > 2 {
+		// out of order
+		(*list)[0], (*list)[len(*list)-1] = (*list)[len(*list)-1], (*list)[0]
+	}
+	return list
+}
+
+type engineConfig struct {
+	columns     []*[]SpanBoundry[int]
+	consolidate bool
+	tagger      bool
+	mode        JoinMode
+	policy      ErrorPolicy
+	dynamic     bool
+	variant     int
+	seed        int64
+}
+
+func engineResults(engine ColumnEngine, reuse bool, conf *engineConfig) []string {
+	var u = engineUtil(conf.variant)
+	u.Sort = false
+	u.Consolidate = conf.consolidate
+	if conf.tagger {
+		u.Tagger = MergeTags[int](func(a, b int) int { return a + b })
+	}
+	var cs = u.NewColumnSets()
+	defer cs.Close()
+	cs.Engine = engine
+	cs.ReuseBuffers = reuse
+	cs.JoinMode = conf.mode
+	cs.MinColumns = 2
+	cs.ErrorPolicy = conf.policy
+	var res = []string{}
+	cs.OnError = func(err *SpanError[int]) {
+		res = append(res, "error: "+err.Error())
+	}
+	var half = max(1, len(conf.columns)/2)
+	for i, list := range conf.columns {
+		if !conf.dynamic || i < half {
+			cs.AddColumnFromSpanSlice(list)
+		}
+	}
+	var r = rand.New(rand.NewSource(conf.seed))
+	var next = half
+	for id, seg := range cs.Iter() {
+		var value, ok = ValueOf[int, int](seg.GetSpan())
+		var bounded = seg.(BoundedSpanBoundry[int])
+		var line = fmt.Sprintf("%d: %T [%v %v %v %v] tag: %v %v", id, leafSpan(seg.GetSpan()), seg.GetBegin(), bounded.BeginInclusive(), seg.GetEnd(), bounded.EndInclusive(), value, ok)
+		for _, col := range *seg.GetColumns() {
+			line += fmt.Sprintf(" col: %d (%d-%d)", col.ColumnId, col.GetSrcId(), col.GetEndId())
+			for _, src := range *col.GetSources() {
+				line += fmt.Sprintf(" %d", src.SrcId)
+			}
+		}
+		res = append(res, line)
+		if conf.dynamic {
+			if next < len(conf.columns) && r.Intn(3) == 0 {
+				cs.AddColumnFromSpanSlice(conf.columns[next])
+				next++
+			}
+			if r.Intn(5) == 0 {
+				cs.RemoveColumn(r.Intn(next))
+			}
+		}
+	}
+	if cs.Err != nil {
+		res = append(res, "Err: "+cs.Err.Error())
+	}
+	return res
+}
+
+func TestHeapEngineDifferential(t *testing.T) {
+	var r = rand.New(rand.NewSource(18))
+	for i := range 1000 {
+		var conf = &engineConfig{
+			consolidate: r.Intn(2) == 0,
+			tagger:      r.Intn(2) == 0,
+			mode:        JoinMode(r.Intn(6)),
+			policy:      ErrorPolicy(r.Intn(2)),
+			dynamic:     r.Intn(3) == 0,
+			variant:     r.Intn(3),
+			seed:        r.Int63(),
+		}
+		var u = engineUtil(conf.variant)
+		var bounded = r.Intn(2) == 0
+		for range 1 + r.Intn(8) {
+			conf.columns = append(conf.columns, randomSpans(r, u, r.Intn(15), bounded))
+		}
+		// every engine gets a copy of the lists, so they all see the same data
+		var orig = conf.columns
+		var results = func(engine ColumnEngine, reuse bool) []string {
+			conf.columns = []*[]SpanBoundry[int]{}
+			for _, list := range orig {
+				var c = append([]SpanBoundry[int]{}, *list...)
+				conf.columns = append(conf.columns, &c)
+			}
+			return engineResults(engine, reuse, conf)
+		}
+		var expected = results(LinearEngine, false)
+		for _, engine := range []ColumnEngine{LinearEngine, HeapEngine} {
+			for _, reuse := range []bool{false, true} {
+				var res = results(engine, reuse)
+				if len(res) != len(expected) {
+					t.Errorf("Run %d, engine: %d, reuse: %v: %+v\nExpected:\n%v\nGot:\n%v", i, engine, reuse, *conf, expected, res)
+					return
+				}
+				for j := range expected {
+					if res[j] != expected[j] {
+						t.Errorf("Run %d, engine: %d, reuse: %v: %+v\nExpected: %s\nGot:      %s", i, engine, reuse, *conf, expected[j], res[j])
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestHeapEngineVisitsCrossedColumns(t *testing.T) {
+	var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	var cs = u.NewColumnSets()
+	defer cs.Close()
+	cs.Engine = HeapEngine
+	for i := range 100 {
+		cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{u.Nb(i*2, true, 1000, false)})
+	}
+	var steps, visits = 0, 0
+	for _, seg := range cs.Iter() {
+		if seg.OverlapCount() != min(steps+1, 100) {
+			t.Fatalf("Step %d, expected %d columns, got: %d", steps, min(steps+1, 100), seg.OverlapCount())
+		}
+		steps++
+		visits += len(cs.visit)
+	}
+	if steps != 100 {
+		t.Errorf("Expected 100 segments, got: %d", steps)
+	}
+	// the live columns are not visited again until they end
+	if visits > 2*steps+100 {
+		t.Errorf("Expected at most %d visits, got: %d", 2*steps+100, visits)
+	}
+}
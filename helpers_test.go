@@ -0,0 +1,81 @@
+package st
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// Returns a random closed span, or a random half open span when bounded is false, otherwise a span with random
+// bounds.  The span begins before limit, and is at most width values long.
+func randomSpan(r *rand.Rand, u *SpanUtil[int], limit, width int, bounded bool) SpanBoundry[int] {
+	var begin = r.Intn(limit)
+	switch {
+	case r.Intn(2) == 0:
+		return u.Ns(begin, begin+r.Intn(width))
+	case bounded:
+		return u.Nb(begin, r.Intn(2) == 0, begin+1+r.Intn(width), r.Intn(2) == 0)
+	}
+	return u.HalfOpen(begin, begin+1+r.Intn(width))
+}
+
+// The expected value of every point a random span can contain, see: randomSpan.
+type pointOracle [120]int
+
+// Sets every point in span to value.
+func (o *pointOracle) set(u *SpanUtil[int], span SpanBoundry[int], value int) {
+	for p := range o {
+		if u.Contains(span, p) {
+			o[p] = value
+		}
+	}
+}
+
+// Adds delta to every point in span.
+func (o *pointOracle) add(u *SpanUtil[int], span SpanBoundry[int], delta int) {
+	for p := range o {
+		if u.Contains(span, p) {
+			o[p] += delta
+		}
+	}
+}
+
+// Returns the values of the points in span.
+func (o *pointOracle) values(u *SpanUtil[int], span SpanBoundry[int]) []int {
+	var res = []int{}
+	for p := range o {
+		if u.Contains(span, p) {
+			res = append(res, o[p])
+		}
+	}
+	return res
+}
+
+// Returns an error for the first point where get does not return the expected value.
+func (o *pointOracle) check(name string, get func(p int) int) error {
+	for p, expected := range o {
+		if res := get(p); res != expected {
+			return fmt.Errorf("%s(%d) Expected: %d, got: %d", name, p, expected, res)
+		}
+	}
+	return nil
+}
+
+// Calls step for each step of a randomized test, the test fails at the first error.
+func runSteps(t *testing.T, name string, steps int, step func(i int) error) {
+	t.Helper()
+	for i := range steps {
+		if err := step(i); err != nil {
+			t.Fatalf("%s, step %d: %v", name, i, err)
+		}
+	}
+}
+
+// Returns the begin, end and OverlapCount of every segment produced by cs.
+func segmentCounts(cs *ColumnSets[int]) [][3]int {
+	var res = [][3]int{}
+	for _, seg := range cs.Iter() {
+		res = append(res, [3]int{seg.GetBegin(), seg.GetEnd(), seg.OverlapCount()})
+	}
+	return res
+}
@@ -0,0 +1,156 @@
+package st
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// Returns the height of n, after checking the balance, order, size and max of every node.
+func checkIntervalNode(tree *IntervalTree[int], n *intervalNode[int], t *testing.T) int {
+	if n == nil {
+		return 0
+	}
+	var u = tree.Util
+	var left, right = checkIntervalNode(tree, n.left, t), checkIntervalNode(tree, n.right, t)
+	if left-right > 1 || right-left > 1 {
+		t.Errorf("Node %v is not balanced: %d, %d", n.span, left, right)
+	}
+	var largest = n.end
+	var size = 1
+	for _, child := range []*intervalNode[int]{n.left, n.right} {
+		if child != nil {
+			size += child.size
+			if u.cmpBoundry(child.max, largest) > 0 {
+				largest = child.max
+			}
+		}
+	}
+	if n.left != nil && tree.cmpNode(n.left, n) > -1 || n.right != nil && tree.cmpNode(n.right, n) < 1 {
+		t.Errorf("Node %v is out of order", n.span)
+	}
+	if u.cmpBoundry(largest, n.max) != 0 || size != n.size {
+		t.Errorf("Node %v has an invalid max or size", n.span)
+	}
+	return 1 + max(left, right)
+}
+
+func collectSpans(seq func(func(SpanBoundry[int]) bool)) []SpanBoundry[int] {
+	var res = []SpanBoundry[int]{}
+	for span := range seq {
+		res = append(res, span)
+	}
+	return res
+}
+
+func TestIntervalTree(t *testing.T) {
+	var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	var r = rand.New(rand.NewSource(20))
+	var tree = u.NewIntervalTree()
+	var list = []SpanBoundry[int]{}
+	var versions = []*IntervalTree[int]{}
+	var lists = [][]SpanBoundry[int]{}
+	runSteps(t, "IntervalTree", 2000, func(i int) error {
+		if len(list) != 0 && r.Intn(3) == 0 {
+			var span = list[r.Intn(len(list))]
+			var ok bool
+			if tree, ok = tree.Delete(span); !ok {
+				return fmt.Errorf("span %v not found", span)
+			}
+			list = slices.DeleteFunc(list, func(s SpanBoundry[int]) bool { return s == span })
+		} else {
+			var span = randomSpan(r, u, 200, 20, true)
+			var err error
+			if tree, err = tree.Insert(span); err != nil {
+				return err
+			}
+			list = append(list, span)
+		}
+		if i%100 == 0 {
+			versions = append(versions, tree)
+			lists = append(lists, slices.Clone(list))
+		}
+		return nil
+	})
+	versions = append(versions, tree)
+	lists = append(lists, list)
+
+	for v, tree := range versions {
+		checkIntervalNode(tree, tree.root, t)
+		var list = lists[v]
+		slices.SortStableFunc(list, u.Compare)
+		if tree.Len() != len(list) {
+			t.Fatalf("Version %d: Expected Len: %d, got: %d", v, len(list), tree.Len())
+		}
+		var all = *tree.Spans()
+		for i := range list {
+			if u.Compare(list[i], all[i]) != 0 {
+				t.Fatalf("Version %d: Expected: %v, got: %v", v, list, all)
+			}
+		}
+		for point := -1; point < 222; point += 3 {
+			var expected = slices.DeleteFunc(slices.Clone(all), func(s SpanBoundry[int]) bool { return !u.Contains(s, point) })
+			if res := collectSpans(tree.Stab(point)); !slices.Equal(res, expected) {
+				t.Errorf("Version %d: Stab(%d) Expected: %v, got: %v", v, point, expected, res)
+			}
+			var query = u.HalfOpen(point, point+5)
+			expected = slices.DeleteFunc(slices.Clone(all), func(s SpanBoundry[int]) bool { return !u.Overlap(s, query) })
+			if res := collectSpans(tree.Query(query)); !slices.Equal(res, expected) {
+				t.Errorf("Version %d: Query(%v) Expected: %v, got: %v", v, query, expected, res)
+			}
+		}
+	}
+}
+
+func TestIntervalTreeFromSortedSlice(t *testing.T) {
+	var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	var list = &[]SpanBoundry[int]{u.Ns(1, 5), u.Ns(2, 3), u.Ns(2, 2), u.Ns(4, 9), u.Ns(12, 20)}
+	var tree, err = u.NewIntervalTreeFromSortedSlice(list)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkIntervalNode(tree, tree.root, t)
+	if res := collectSpans(tree.Stab(2)); !slices.Equal(res, (*list)[:3]) {
+		t.Errorf("Expected: %v, got: %v", (*list)[:3], res)
+	}
+
+	// spans with the same bounds, remove the same instance
+	var dup = u.Ns(2, 2)
+	var next, _ = tree.Insert(dup)
+	next, _ = next.Delete(dup)
+	if res := collectSpans(next.Stab(2)); !slices.Equal(res, (*list)[:3]) {
+		t.Errorf("Expected: %v, got: %v", (*list)[:3], res)
+	}
+	if _, ok := next.Delete(u.Ns(7, 8)); ok {
+		t.Errorf("Deleted a span that is not in the tree")
+	}
+	if tree.Len() != 5 || next.Len() != 5 {
+		t.Errorf("Expected Len: 5, got: %d, %d", tree.Len(), next.Len())
+	}
+
+	_, err = u.NewIntervalTreeFromSortedSlice(&[]SpanBoundry[int]{u.Ns(1, 5), u.Ns(4, 9), u.Ns(2, 3)})
+	var spanErr *SpanError[int]
+	if !errors.As(err, &spanErr) || !errors.Is(err, ErrOutOfSequence) || spanErr.Pos != 2 {
+		t.Errorf("Expected ErrOutOfSequence at Pos 2, got: %v", err)
+	}
+	if _, err = tree.Insert(u.Ns(5, 1)); !errors.Is(err, ErrBeginAfterEnd) {
+		t.Errorf("Expected ErrBeginAfterEnd, got: %v", err)
+	}
+}
+
+func TestIntervalTreeColumn(t *testing.T) {
+	var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	var tree = u.NewIntervalTree()
+	for _, span := range []SpanBoundry[int]{u.HalfOpen(10, 20), u.HalfOpen(0, 5)} {
+		tree, _ = tree.Insert(span)
+	}
+	var cs = u.NewColumnSets()
+	cs.AddColumnFromSpanSlice(tree.Spans())
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{u.HalfOpen(3, 12)})
+	var expected = [][3]int{{0, 3, 1}, {3, 5, 2}, {5, 10, 1}, {10, 12, 2}, {12, 20, 1}}
+	if res := segmentCounts(cs); !slices.Equal(res, expected) {
+		t.Errorf("Expected: %v, got: %v", expected, res)
+	}
+}
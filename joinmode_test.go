@@ -0,0 +1,120 @@
+package st
+
+import (
+	"testing"
+)
+
+func joinColumns(mode JoinMode) *ColumnSets[int] {
+	var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	var cs = u.NewColumnSets()
+	cs.JoinMode = mode
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{u.HalfOpen(1, 10)})
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{u.HalfOpen(3, 5), u.HalfOpen(8, 12)})
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{u.HalfOpen(4, 9), u.HalfOpen(14, 15)})
+	return cs
+}
+
+type joinResult struct {
+	Begin int
+	End   int
+	Count int
+}
+
+func collectJoin(cs *ColumnSets[int]) []joinResult {
+	var res = []joinResult{}
+	for id, col := range cs.Iter() {
+		if id != len(res) {
+			return nil
+		}
+		res = append(res, joinResult{col.GetBegin(), col.GetEnd(), col.OverlapCount()})
+	}
+	return res
+}
+
+func CommonJoinResult(name string, res, expected []joinResult, t *testing.T) {
+	if len(res) != len(expected) {
+		t.Errorf("%s: Expected %v, got: %v", name, expected, res)
+		return
+	}
+	for i, r := range expected {
+		if res[i] != r {
+			t.Errorf("%s: Expected %v, got: %v", name, expected, res)
+			return
+		}
+	}
+}
+
+func TestJoinModes(t *testing.T) {
+	CommonJoinResult("full outer", collectJoin(joinColumns(JoinFullOuter)), []joinResult{
+		{1, 3, 1}, {3, 4, 2}, {4, 5, 3}, {5, 8, 2}, {8, 9, 3}, {9, 10, 2}, {10, 12, 1}, {12, 14, 0}, {14, 15, 1},
+	}, t)
+	CommonJoinResult("any", collectJoin(joinColumns(JoinAny)), []joinResult{
+		{1, 3, 1}, {3, 4, 2}, {4, 5, 3}, {5, 8, 2}, {8, 9, 3}, {9, 10, 2}, {10, 12, 1}, {14, 15, 1},
+	}, t)
+	CommonJoinResult("inner", collectJoin(joinColumns(JoinInner)), []joinResult{{4, 5, 3}, {8, 9, 3}}, t)
+	CommonJoinResult("left", collectJoin(joinColumns(JoinLeft)), []joinResult{
+		{1, 3, 1}, {3, 4, 2}, {4, 5, 3}, {5, 8, 2}, {8, 9, 3}, {9, 10, 2},
+	}, t)
+	CommonJoinResult("anti", collectJoin(joinColumns(JoinAnti)), []joinResult{{1, 3, 1}}, t)
+
+	var cs = joinColumns(JoinAtLeast)
+	cs.MinColumns = 2
+	CommonJoinResult("at least", collectJoin(cs), []joinResult{{3, 4, 2}, {4, 5, 3}, {5, 8, 2}, {8, 9, 3}, {9, 10, 2}}, t)
+}
+
+func TestJoinFullOuterGaps(t *testing.T) {
+	for _, engine := range []ColumnEngine{LinearEngine, HeapEngine} {
+		var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+		u.Prev = func(e int) int { return e - 1 }
+		var cs = u.NewColumnSets()
+		cs.Engine = engine
+		cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{u.Ns(1, 2), u.Ns(5, 6)})
+		CommonJoinResult("gap with Prev", collectJoin(cs), []joinResult{{1, 2, 1}, {3, 4, 0}, {5, 6, 1}}, t)
+
+		// without Prev the gap before a closed span ends at its begin value
+		u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+		cs = u.NewColumnSets()
+		cs.Engine = engine
+		cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{u.Ns(1, 2), u.Ns(5, 6)})
+		var res = []joinResult{}
+		for _, col := range cs.Iter() {
+			res = append(res, joinResult{col.GetBegin(), col.GetEnd(), col.OverlapCount()})
+			if _, ok := col.GetSpan().(*Span[int]); !ok {
+				t.Errorf("Expected a closed Span, got: %v", col.GetSpan())
+			}
+		}
+		CommonJoinResult("gap without Prev", res, []joinResult{{1, 2, 1}, {3, 5, 1}, {6, 6, 1}}, t)
+
+		cs = u.NewColumnSets()
+		cs.Engine = engine
+		cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{u.Ns(1, 2), u.HalfOpen(5, 7)})
+		res = []joinResult{}
+		for _, col := range cs.Iter() {
+			res = append(res, joinResult{col.GetBegin(), col.GetEnd(), col.OverlapCount()})
+			if col.GetBegin() == 3 && spanEndInclusive(col.GetSpan()) {
+				t.Errorf("Expected the gap to end just before 5, got: %v", col.GetSpan())
+			}
+		}
+		CommonJoinResult("bounded gap without Prev", res, []joinResult{{1, 2, 1}, {3, 5, 0}, {5, 7, 1}}, t)
+	}
+}
+
+func TestJoinInnerRemoveColumn(t *testing.T) {
+	for _, engine := range []ColumnEngine{LinearEngine, HeapEngine} {
+		var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+		var cs = u.NewColumnSets()
+		cs.Engine = engine
+		cs.JoinMode = JoinInner
+		cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{u.HalfOpen(1, 20)})
+		cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{u.HalfOpen(2, 4), u.HalfOpen(8, 12)})
+		cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{u.HalfOpen(3, 10)})
+		var res = []joinResult{}
+		for _, col := range cs.Iter() {
+			res = append(res, joinResult{col.GetBegin(), col.GetEnd(), col.OverlapCount()})
+			if len(res) == 1 && !cs.RemoveColumn(2) {
+				t.Errorf("Expected column 2 to be removed")
+			}
+		}
+		CommonJoinResult("inner after RemoveColumn", res, []joinResult{{3, 4, 3}, {8, 12, 2}}, t)
+	}
+}
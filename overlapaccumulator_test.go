@@ -1,8 +1,11 @@
 package st
 
 import (
+	"context"
+	"errors"
 	"iter"
 	"testing"
+	"time"
 )
 
 func TestOverlapsLoopBreak(t *testing.T) {
@@ -128,3 +131,76 @@ func TestOverlapAdjacentConsolidate(t *testing.T) {
 		t.Errorf("Iterator count missmatch!, expected %d, got %d", len(expected), count)
 	}
 }
+
+func TestOlssChanStaterContext(t *testing.T) {
+	var ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	var s = testDriver.NewSpanOverlapAccumulator().NewOlssChanStaterContext(ctx)
+	defer s.Shutdown()
+	var stopped = accumulateEndless(s, 0)
+	var list = []*OverlappingSpanSets[int]{}
+	for ol := range s.Chan {
+		list = append(list, ol)
+		if len(list) == 5 {
+			cancel()
+		}
+	}
+	waitFor(t, stopped)
+	for i, ol := range list {
+		if ol.Err != nil || ol.GetBegin() != i {
+			t.Fatalf("Expected span %d without an error, got: %v, %v", i, ol.Span, ol.Err)
+		}
+	}
+	if !errors.Is(s.Err, context.Canceled) {
+		t.Errorf("Expected Err to be context.Canceled, got: %v", s.Err)
+	}
+}
+
+func TestOlssChanStaterCancelWithoutDraining(t *testing.T) {
+	var s = testDriver.NewSpanOverlapAccumulator().NewOlssChanStater()
+	var stopped = accumulateEndless(s, 0)
+	for range 2 {
+		if ol := <-s.Chan; ol.Err != nil {
+			t.Fatalf("Expected no error, got: %v", ol.Err)
+		}
+	}
+	// nothing reads from the channel after the cancel
+	s.Cancel()
+	waitFor(t, stopped)
+	if !errors.Is(s.Err, context.Canceled) {
+		t.Errorf("Expected Err to be context.Canceled, got: %v", s.Err)
+	}
+	for ol := range s.Chan {
+		if ol.Err != nil || ol.Span == nil {
+			t.Errorf("Expected only spans from the go routine, got: %v, %v", ol.Span, ol.Err)
+		}
+	}
+}
+
+func TestOlssChanStaterContextDeadline(t *testing.T) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	var cs = testDriver.NewColumnSets()
+	defer cs.Close()
+	var s = testDriver.NewSpanOverlapAccumulator().NewOlssChanStaterContext(ctx)
+	var stopped = accumulateEndless(s, 0)
+	cs.AddColumnFromNewOlssChanStater(s)
+	for range cs.Iter() {
+	}
+	var err *SpanError[int]
+	if !errors.Is(cs.Err, context.DeadlineExceeded) || !errors.As(cs.Err, &err) || err.ColumnId != 0 {
+		t.Errorf("Expected context.DeadlineExceeded from column 0, got: %v", cs.Err)
+	}
+	waitFor(t, stopped)
+}
+
+func TestOlssChanStaterShutdown(t *testing.T) {
+	var s = testDriver.NewSpanOverlapAccumulator().NewOlssChanStater()
+	var stopped = accumulateEndless(s, 0)
+	if ol := <-s.Chan; ol.Err != nil {
+		t.Fatalf("Expected no error, got: %v", ol.Err)
+	}
+	// nothing reads from the channel after the shutdown
+	s.Shutdown()
+	waitFor(t, stopped)
+}
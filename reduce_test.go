@@ -0,0 +1,100 @@
+package st
+
+import (
+	"testing"
+)
+
+type reduceResult[V comparable] struct {
+	Begin int
+	End   int
+	Value V
+}
+
+func reduceColumns() *ColumnSets[int] {
+	var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	var cs = u.NewColumnSets()
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{NewTaggedSpan(u.Ns(1, 10), 5)})
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{
+		NewTaggedSpan(u.Ns(3, 4), 2),
+		NewTaggedSpan(u.Ns(5, 6), 2),
+	})
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{NewTaggedSpan(u.Ns(9, 12), 7)})
+	return cs
+}
+
+func collectReduce[V comparable](cs *ColumnSets[int], reducer func(SpanBoundry[int], *[]*CurrentColumn[int]) V) []reduceResult[V] {
+	var res = []reduceResult[V]{}
+	for span, value := range Reduce(cs, reducer) {
+		res = append(res, reduceResult[V]{span.GetBegin(), span.GetEnd(), value})
+	}
+	return res
+}
+
+func CommonReduceResult[V comparable](name string, res, expected []reduceResult[V], t *testing.T) {
+	if len(res) != len(expected) {
+		t.Errorf("%s: Expected %v, got: %v", name, expected, res)
+		return
+	}
+	for i, r := range expected {
+		if res[i] != r {
+			t.Errorf("%s: Expected %v, got: %v", name, expected, res)
+			return
+		}
+	}
+}
+
+func TestReduceCount(t *testing.T) {
+	var res = collectReduce(reduceColumns(), CountColumns[int]())
+	CommonReduceResult("count", res, []reduceResult[int]{{1, 1, 1}, {2, 10, 2}, {11, 12, 1}}, t)
+
+	var cs = reduceColumns()
+	cs.ReuseBuffers = true
+	res = collectReduce(cs, CountColumns[int]())
+	CommonReduceResult("count with ReuseBuffers", res, []reduceResult[int]{{1, 1, 1}, {2, 10, 2}, {11, 12, 1}}, t)
+}
+
+func TestReduceSum(t *testing.T) {
+	var res = collectReduce(reduceColumns(), SumValues[int, int]())
+	CommonReduceResult("sum", res, []reduceResult[int]{{1, 1, 5}, {2, 6, 7}, {7, 10, 12}, {11, 12, 7}}, t)
+}
+
+func TestReduceMinMax(t *testing.T) {
+	var res = collectReduce(reduceColumns(), MinValue[int, int]())
+	CommonReduceResult("min", res, []reduceResult[int]{{1, 1, 5}, {2, 6, 2}, {7, 10, 5}, {11, 12, 7}}, t)
+	res = collectReduce(reduceColumns(), MaxValue[int, int]())
+	CommonReduceResult("max", res, []reduceResult[int]{{1, 6, 5}, {7, 12, 7}}, t)
+}
+
+func TestReducePriority(t *testing.T) {
+	var priority = []int{1, 3, 2}
+	var res = collectReduce(reduceColumns(), HighestPriority[int](func(id int) int { return priority[id] }))
+	CommonReduceResult("priority", res, []reduceResult[int]{{1, 1, 0}, {2, 6, 1}, {7, 12, 2}}, t)
+
+	var cs = reduceColumns()
+	for range cs.Iter() {
+		break
+	}
+	if len(collectReduce(cs, CountColumns[int]())) != 0 {
+		t.Errorf("Expected no results, once Iter has been called")
+	}
+}
+
+func overlappingRowColumns() *ColumnSets[int] {
+	var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	var cs = u.NewColumnSets()
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{
+		NewTaggedSpan(u.Ns(1, 3), 100),
+		NewTaggedSpan(u.Ns(2, 10), 1),
+	})
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{NewTaggedSpan(u.Ns(8, 12), 5)})
+	return cs
+}
+
+func TestReduceOverlappingRows(t *testing.T) {
+	var res = collectReduce(overlappingRowColumns(), SumValues[int, int]())
+	CommonReduceResult("sum", res, []reduceResult[int]{{1, 1, 100}, {2, 8, 106}, {9, 10, 6}, {11, 12, 5}}, t)
+	res = collectReduce(overlappingRowColumns(), MinValue[int, int]())
+	CommonReduceResult("min", res, []reduceResult[int]{{1, 1, 100}, {2, 10, 1}, {11, 12, 5}}, t)
+	res = collectReduce(overlappingRowColumns(), MaxValue[int, int]())
+	CommonReduceResult("max", res, []reduceResult[int]{{1, 8, 100}, {9, 12, 5}}, t)
+}
@@ -0,0 +1,136 @@
+package st
+
+import (
+	"testing"
+	"time"
+)
+
+func reorderResults(ac *SpanOverlapAccumulator[int], list *[]SpanBoundry[int]) [][2]int {
+	var res = [][2]int{}
+	for _, ol := range ac.NewOlssSeq2FromSbSlice(list) {
+		res = append(res, [2]int{ol.GetBegin(), ol.GetEnd()})
+	}
+	return res
+}
+
+func CommonReorderResult(name string, res, expected [][2]int, t *testing.T) {
+	if len(res) != len(expected) {
+		t.Errorf("%s: Expected %v, got: %v", name, expected, res)
+		return
+	}
+	for i, span := range expected {
+		if res[i] != span {
+			t.Errorf("%s: Expected %v, got: %v", name, expected, res)
+			return
+		}
+	}
+}
+
+func TestReorderWindow(t *testing.T) {
+	var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	u.Sort = false
+	var list = &[]SpanBoundry[int]{
+		u.Ns(3, 4),
+		u.Ns(1, 1),
+		u.Ns(7, 9),
+		u.Ns(5, 5),
+		u.Ns(2, 3),
+		u.Ns(11, 12),
+	}
+
+	var ac = u.NewSpanOverlapAccumulator()
+	reorderResults(ac, list)
+	if ac.Err == nil {
+		t.Errorf("Expected an error without a reorder buffer")
+	}
+
+	ac = u.NewSpanOverlapAccumulator()
+	ac.ReorderWindow = 3
+	var res = reorderResults(ac, list)
+	if ac.Err != nil {
+		t.Errorf("Expected no error, got: %v", ac.Err)
+	}
+	CommonReorderResult("window", res, [][2]int{{1, 1}, {2, 4}, {5, 5}, {7, 9}, {11, 12}}, t)
+
+	ac = u.NewSpanOverlapAccumulator()
+	ac.ReorderWindow = 2
+	reorderResults(ac, list)
+	if ac.Err == nil {
+		t.Errorf("Expected an error, when the window is too small")
+	}
+}
+
+func TestReorderLateness(t *testing.T) {
+	var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	u.Sort = false
+	var ac = u.NewSpanOverlapAccumulator()
+	ac.MaxLateness = Lateness(3)
+	var list = &[]SpanBoundry[int]{
+		u.Ns(3, 4),
+		u.Ns(1, 1),
+		u.Ns(20, 21),
+		u.Ns(18, 19),
+		u.Ns(30, 30),
+	}
+	var res = reorderResults(ac, list)
+	if ac.Err != nil {
+		t.Errorf("Expected no error, got: %v", ac.Err)
+	}
+	CommonReorderResult("lateness", res, [][2]int{{1, 1}, {3, 4}, {18, 19}, {20, 21}, {30, 30}}, t)
+
+	ac = u.NewSpanOverlapAccumulator()
+	ac.MaxLateness = Lateness(3)
+	*list = append(*list, u.Ns(19, 19))
+	reorderResults(ac, list)
+	if ac.Err == nil {
+		t.Errorf("Expected an error, for a span that is too late")
+	}
+
+	var late = TimeLateness(time.Minute)
+	var now = time.Now()
+	if late(now, now.Add(time.Second)) || !late(now, now.Add(time.Minute)) {
+		t.Errorf("Invalid TimeLateness results")
+	}
+}
+
+func TestRepairInverted(t *testing.T) {
+	var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	u.Sort = false
+	var ac = u.NewSpanOverlapAccumulator()
+	ac.RepairInverted = true
+	var res = reorderResults(ac, &[]SpanBoundry[int]{u.Ns(2, 1), u.Ns(5, 3)})
+	if ac.Err != nil {
+		t.Errorf("Expected no error, got: %v", ac.Err)
+	}
+	CommonReorderResult("repair", res, [][2]int{{1, 2}, {3, 5}}, t)
+
+	var span = u.Repair(u.Nb(5, true, 3, false))
+	if span.GetBegin() != 3 || span.GetEnd() != 5 || spanBeginInclusive(span) || !spanEndInclusive(span) {
+		t.Errorf("Invalid repaired span: %v", span)
+	}
+}
+
+func TestReorderChan(t *testing.T) {
+	var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	u.Sort = false
+	var ac = u.NewSpanOverlapAccumulator()
+	ac.MaxLateness = Lateness(100)
+	var s = ac.NewOlssChanStater()
+	defer s.Shutdown()
+	go func() {
+		defer s.Final()
+		for _, span := range []SpanBoundry[int]{u.Ns(9, 9), u.Ns(5, 5), u.Ns(1, 2), u.Ns(2, 3)} {
+			if !s.CanAccumulate(span) {
+				return
+			}
+		}
+	}()
+	var res = [][2]int{}
+	for ol := range s.Chan {
+		res = append(res, [2]int{ol.GetBegin(), ol.GetEnd()})
+	}
+	if ac.Err != nil {
+		t.Errorf("Expected no error, got: %v", ac.Err)
+	}
+	CommonReorderResult("chan", res, [][2]int{{1, 3}, {5, 5}, {9, 9}}, t)
+}
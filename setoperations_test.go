@@ -0,0 +1,120 @@
+package st
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+)
+
+func SubOne(e int) int {
+	return e - 1
+}
+
+var setDriver = NewSpanUtilWithPrev(cmp.Compare, AddOne, SubOne)
+
+func CommonSetResult(name string, res *[]SpanBoundry[int], expected [][2]int, t *testing.T) {
+	if len(*res) != len(expected) {
+		t.Errorf("%s: expected %d spans, got %d: %v", name, len(expected), len(*res), *res)
+		return
+	}
+	for i, span := range *res {
+		if span.GetBegin() != expected[i][0] || span.GetEnd() != expected[i][1] {
+			t.Errorf("%s: span %d expected: %v, got: %v", name, i, expected[i], span)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	var list = []SpanBoundry[int]{
+		&Span[int]{Begin: 5, End: 6},
+		&Span[int]{Begin: 1, End: 3},
+		&Span[int]{Begin: 2, End: 4},
+		&Span[int]{Begin: 9, End: 8},
+	}
+	var cp = slices.Clone(list)
+	CommonSetResult("Normalize", setDriver.Normalize(&list), [][2]int{{1, 4}, {5, 6}}, t)
+	if !slices.Equal(cp, list) {
+		t.Error("Normalize should never modify the list passed in")
+	}
+	CommonSetResult("Normalize nil", setDriver.Normalize(nil), [][2]int{}, t)
+
+	setDriver.Consolidate = true
+	defer func() { setDriver.Consolidate = false }()
+	CommonSetResult("Normalize Consolidate", setDriver.Normalize(&list), [][2]int{{1, 6}}, t)
+}
+
+func TestUnionIntersect(t *testing.T) {
+	var a = &[]SpanBoundry[int]{
+		&Span[int]{Begin: 1, End: 5},
+		&Span[int]{Begin: 10, End: 12},
+	}
+	var b = &[]SpanBoundry[int]{
+		&Span[int]{Begin: 3, End: 7},
+		&Span[int]{Begin: 11, End: 11},
+	}
+	var c = &[]SpanBoundry[int]{
+		&Span[int]{Begin: 4, End: 11},
+	}
+	CommonSetResult("Union", setDriver.Union(a, b), [][2]int{{1, 7}, {10, 12}}, t)
+	CommonSetResult("Union 3", setDriver.Union(a, b, c), [][2]int{{1, 12}}, t)
+	CommonSetResult("Intersect", setDriver.Intersect(a, b), [][2]int{{3, 5}, {11, 11}}, t)
+	CommonSetResult("Intersect 3", setDriver.Intersect(a, b, c), [][2]int{{4, 5}, {11, 11}}, t)
+	CommonSetResult("Intersect empty", setDriver.Intersect(), [][2]int{}, t)
+	CommonSetResult("Union Seq", setDriver.Union(setDriver.CollectSpans(slices.Values(*a))), [][2]int{{1, 5}, {10, 12}}, t)
+	CommonSetResult("Empty Seq", setDriver.CollectSpans(slices.Values([]SpanBoundry[int]{})), [][2]int{}, t)
+}
+
+func TestSubtract(t *testing.T) {
+	var a = &[]SpanBoundry[int]{
+		&Span[int]{Begin: 1, End: 10},
+		&Span[int]{Begin: 20, End: 30},
+	}
+	var b = &[]SpanBoundry[int]{
+		&Span[int]{Begin: 3, End: 4},
+		&Span[int]{Begin: 6, End: 6},
+		&Span[int]{Begin: 9, End: 21},
+	}
+	var res, err = setDriver.Subtract(a, b)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+		return
+	}
+	CommonSetResult("Subtract", res, [][2]int{{1, 2}, {5, 5}, {7, 8}, {22, 30}}, t)
+
+	res, err = setDriver.Subtract(b, a)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+		return
+	}
+	CommonSetResult("Subtract reversed", res, [][2]int{{11, 19}}, t)
+
+	res, err = setDriver.SymmetricDifference(a, b)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+		return
+	}
+	CommonSetResult("SymmetricDifference", res, [][2]int{{1, 2}, {5, 5}, {7, 8}, {11, 19}, {22, 30}}, t)
+
+	res, err = setDriver.SymmetricDifference(a, a, a)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+		return
+	}
+	CommonSetResult("SymmetricDifference odd", res, [][2]int{{1, 10}, {20, 30}}, t)
+
+	// no Prev function, we can only carve the right side
+	res, err = testDriver.Subtract(a, &[]SpanBoundry[int]{&Span[int]{Begin: 1, End: 2}})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+		return
+	}
+	CommonSetResult("Subtract no Prev", res, [][2]int{{3, 10}, {20, 30}}, t)
+	_, err = testDriver.Subtract(a, b)
+	if err == nil {
+		t.Error("Expected an error when Prev is nil")
+	}
+	_, err = testDriver.SymmetricDifference(a, b)
+	if err == nil {
+		t.Error("Expected an error when Prev is nil")
+	}
+}
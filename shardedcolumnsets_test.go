@@ -0,0 +1,214 @@
+package st
+
+import (
+	"errors"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+type shardResult struct {
+	Begin, End     int
+	BeginInclusive bool
+	EndInclusive   bool
+	Columns        string
+	Tag            string
+}
+
+// Returns the int payload of span, or an empty string when there is none.
+func tagOf(span SpanBoundry[int]) string {
+	if value, ok := ValueOf[int, int](span); ok {
+		return strconv.Itoa(value)
+	}
+	return ""
+}
+
+func columnIds(ids []int) string {
+	var res = ""
+	for _, id := range ids {
+		res += string(rune('A' + id))
+	}
+	return res
+}
+
+// Returns random columns of closed spans, or of half open spans, some of the spans carry an int payload.
+func randomColumns(r *rand.Rand, columns, spans int, closed bool) []*[]SpanBoundry[int] {
+	var res = []*[]SpanBoundry[int]{}
+	for range columns {
+		var list = &[]SpanBoundry[int]{}
+		var pos = 0
+		for range spans {
+			pos += r.Intn(10)
+			var end = pos + 1 + r.Intn(30)
+			var span = testDriver.HalfOpen(pos, end)
+			if closed {
+				span = testDriver.Ns(pos, end-1)
+			}
+			if r.Intn(2) == 0 {
+				span = NewTaggedSpan(span, r.Intn(100))
+			}
+			*list = append(*list, span)
+		}
+		res = append(res, list)
+	}
+	return res
+}
+
+func singleResults(u *SpanUtil[int], lists []*[]SpanBoundry[int], mode JoinMode) []shardResult {
+	var cs = u.NewColumnSets()
+	defer cs.Close()
+	cs.JoinMode = mode
+	cs.MinColumns = 2
+	for _, list := range lists {
+		cs.AddColumnFromSpanSlice(list)
+	}
+	var res = []shardResult{}
+	for _, seg := range cs.Iter() {
+		var ids = []int{}
+		for _, col := range *seg.GetColumns() {
+			ids = append(ids, col.ColumnId)
+		}
+		var bounded = seg.(BoundedSpanBoundry[int])
+		res = append(res, shardResult{seg.GetBegin(), seg.GetEnd(), bounded.BeginInclusive(), bounded.EndInclusive(), columnIds(ids), tagOf(seg.GetSpan())})
+	}
+	return res
+}
+
+func shardedResults(u *SpanUtil[int], lists []*[]SpanBoundry[int], mode JoinMode, splits ...int) []shardResult {
+	var cs = u.NewShardedColumnSets(splits...)
+	cs.JoinMode = mode
+	cs.MinColumns = 2
+	cs.Workers = 3
+	for _, list := range lists {
+		cs.AddColumnFromSpanSlice(list)
+	}
+	if len(splits) == 0 {
+		cs.SampleSplits(5)
+	}
+	var res = []shardResult{}
+	for id, seg := range cs.Iter() {
+		if id != len(res) {
+			return nil
+		}
+		var ids = []int{}
+		for _, col := range *seg.Columns {
+			ids = append(ids, col.ColumnId)
+		}
+		res = append(res, shardResult{seg.GetBegin(), seg.GetEnd(), seg.BeginInclusive(), seg.EndInclusive(), columnIds(ids), tagOf(seg.GetSpan())})
+	}
+	return res
+}
+
+func CommonShardResult(name string, res, expected []shardResult, t *testing.T) {
+	if len(res) != len(expected) {
+		t.Errorf("%s: Expected %v, got: %v", name, expected, res)
+		return
+	}
+	for i, r := range expected {
+		if res[i] != r {
+			t.Errorf("%s: Expected %v at %d, got: %v", name, r, i, res[i])
+			return
+		}
+	}
+}
+
+func TestShardedColumnSets(t *testing.T) {
+	var lists = []*[]SpanBoundry[int]{
+		{testDriver.HalfOpen(1, 10), testDriver.HalfOpen(40, 50)},
+		{testDriver.HalfOpen(3, 5), testDriver.HalfOpen(8, 12)},
+	}
+	var expected = singleResults(testDriver, lists, JoinFullOuter)
+	CommonShardResult("no splits", shardedResults(testDriver, lists, JoinFullOuter, []int{}...), expected, t)
+	CommonShardResult("splits", shardedResults(testDriver, lists, JoinFullOuter, 4, 9, 11, 20, 45), expected, t)
+	CommonShardResult("splits on begin values", shardedResults(testDriver, lists, JoinFullOuter, 3, 8, 40), expected, t)
+}
+
+// Returns the results with every end made inclusive, so segments that only differ in how a point is written compare equal.
+func inclusiveResults(res []shardResult) []shardResult {
+	for i := range res {
+		if !res[i].BeginInclusive {
+			res[i].Begin, res[i].BeginInclusive = res[i].Begin+1, true
+		}
+		if !res[i].EndInclusive {
+			res[i].End, res[i].EndInclusive = res[i].End-1, true
+		}
+	}
+	return res
+}
+
+func TestShardedDifferential(t *testing.T) {
+	var utils = []*SpanUtil[int]{}
+	for i := range 8 {
+		var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+		u.Consolidate = i&1 != 0
+		if i&2 != 0 {
+			u.Prev = func(e int) int { return e - 1 }
+		}
+		if i&4 != 0 {
+			u.Tagger = MergeTags[int](func(a, b int) int { return a + b })
+		}
+		utils = append(utils, u)
+	}
+	var r = rand.New(rand.NewSource(17))
+	for i := range 160 {
+		var u = utils[i%len(utils)]
+		var lists = randomColumns(r, 1+r.Intn(6), 1+r.Intn(40), i%16 < 8)
+		for _, mode := range []JoinMode{JoinFullOuter, JoinAny, JoinInner, JoinLeft, JoinAnti, JoinAtLeast} {
+			var splits = []int{}
+			for range r.Intn(8) {
+				splits = append(splits, r.Intn(300))
+			}
+			var expected, sampled, split = singleResults(u, lists, mode), shardedResults(u, lists, mode), shardedResults(u, lists, mode, splits...)
+			if u.Prev != nil {
+				expected, sampled, split = inclusiveResults(expected), inclusiveResults(sampled), inclusiveResults(split)
+			}
+			CommonShardResult("sampled", sampled, expected, t)
+			CommonShardResult("random splits", split, expected, t)
+			if t.Failed() {
+				t.Errorf("Failed on run: %d, mode: %d, splits: %v", i, mode, splits)
+				return
+			}
+		}
+	}
+}
+
+func TestShardedTagger(t *testing.T) {
+	var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	u.Tagger = MergeTags[int](func(a, b int) int { return a + b })
+	var lists = []*[]SpanBoundry[int]{
+		{NewTaggedSpan(u.Ns(0, 100), 5)},
+		{NewTaggedSpan(u.Ns(10, 20), 7), NewTaggedSpan(u.Ns(60, 70), 3)},
+	}
+	var expected = singleResults(u, lists, JoinFullOuter)
+	CommonShardResult("split", shardedResults(u, lists, JoinFullOuter, 50), expected, t)
+	CommonShardResult("split in a column", shardedResults(u, lists, JoinFullOuter, 15, 65), expected, t)
+}
+
+func TestShardedSources(t *testing.T) {
+	var cs = testDriver.NewShardedColumnSets(5)
+	cs.AddNamedColumnFromSpanSlice("SetA", 1, &[]SpanBoundry[int]{testDriver.HalfOpen(1, 10)})
+	var count = 0
+	for _, seg := range cs.Iter() {
+		count++
+		var col = (*seg.Columns)[0]
+		if col.Name != "SetA" || col.Meta != 1 || len(*col.Sources) != 1 || (*col.Sources)[0].SrcId != 0 || (*col.Sources)[0].GetEnd() != 10 {
+			t.Errorf("Invalid column: %v", col)
+		}
+	}
+	if count != 1 {
+		t.Errorf("Expected a single stitched segment, got: %d", count)
+	}
+	for range cs.Iter() {
+		t.Errorf("Iter should only run once")
+	}
+
+	cs = testDriver.NewShardedColumnSets(5)
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{testDriver.HalfOpen(1, 10)})
+	cs.AddNamedColumnFromSpanSlice("SetB", nil, &[]SpanBoundry[int]{testDriver.HalfOpen(10, 1)})
+	for range cs.Iter() {
+	}
+	var spanErr *SpanError[int]
+	if !errors.As(cs.Err, &spanErr) || !errors.Is(spanErr, ErrBeginAfterEnd) || spanErr.ColumnName != "SetB" || spanErr.ColumnId != 1 {
+		t.Errorf("Expected an error from SetB, got: %v", cs.Err)
+	}
+}
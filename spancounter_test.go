@@ -0,0 +1,95 @@
+package st
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestSpanCounter(t *testing.T) {
+	for _, withPrev := range []bool{false, true} {
+		var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+		if withPrev {
+			u.Prev = func(e int) int { return e - 1 }
+		}
+		var r = rand.New(rand.NewSource(22))
+		var counter = u.NewSpanCounter()
+		var depth = pointOracle{}
+		var added = []SpanBoundry[int]{}
+		runSteps(t, fmt.Sprintf("prev: %v", withPrev), 1000, func(i int) error {
+			if len(added) != 0 && r.Intn(3) == 0 {
+				var j = r.Intn(len(added))
+				var span = added[j]
+				added = append(added[:j], added[j+1:]...)
+				depth.add(u, span, -1)
+				if err := counter.Remove(span); err != nil {
+					return err
+				}
+			} else {
+				var span = randomSpan(r, u, 100, 12, false)
+				added = append(added, span)
+				depth.add(u, span, 1)
+				if err := counter.Add(span); err != nil {
+					return err
+				}
+			}
+
+			if err := depth.check("Depth", counter.Depth); err != nil {
+				return err
+			}
+			var check = randomSpan(r, u, 100, 10, false)
+			if expected, res := slices.Max(depth.values(u, check)), counter.MaxDepth(check); res != expected {
+				return fmt.Errorf("MaxDepth(%v) Expected: %d, got: %d", check, expected, res)
+			}
+
+			var seen = pointOracle{}
+			var last SpanBoundry[int]
+			var lastDepth = 0
+			for seg, d := range counter.Segments() {
+				if _, ok := seg.(BoundedSpanBoundry[int]); withPrev && ok && !u.Overlap(seg, u.Ns(seg.GetEnd(), seg.GetEnd())) {
+					return fmt.Errorf("Expected inclusive end values with Prev, got: %v", seg)
+				}
+				if last != nil && d == lastDepth && !u.Overlap(last, seg) {
+					var next, _ = u.after(u.endOf(last))
+					if u.cmpBoundry(next, u.beginOf(seg)) == 0 {
+						return fmt.Errorf("adjacent segments with the same depth: %v, %v", last, seg)
+					}
+				}
+				seen.add(u, seg, d)
+				last, lastDepth = seg, d
+			}
+			if seen != depth {
+				return fmt.Errorf("Segments Expected: %v, got: %v", depth, seen)
+			}
+			return nil
+		})
+	}
+}
+
+func TestSpanCounterErrors(t *testing.T) {
+	var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	u.SetInfinity(math.MinInt, math.MaxInt)
+	var counter = u.NewSpanCounter()
+	counter.Add(u.NsFrom(10))
+	counter.Add(u.Ns(5, 12))
+	if err := counter.Remove(u.Ns(4, 6)); !errors.Is(err, ErrNegativeDepth) || counter.Depth(5) != 1 {
+		t.Errorf("Expected ErrNegativeDepth, got: %v", err)
+	}
+	if err := counter.Add(u.Ns(6, 5)); !errors.Is(err, ErrBeginAfterEnd) {
+		t.Errorf("Expected ErrBeginAfterEnd, got: %v", err)
+	}
+	var res = [][3]int{}
+	for span, depth := range counter.Segments() {
+		res = append(res, [3]int{span.GetBegin(), span.GetEnd(), depth})
+	}
+	var expected = [][3]int{{5, 10, 1}, {10, 13, 2}, {13, math.MaxInt, 1}}
+	if !slices.Equal(res, expected) {
+		t.Errorf("Expected: %v, got: %v", expected, res)
+	}
+	if counter.MaxDepth(u.NsFrom(13)) != 1 || counter.Depth(math.MaxInt-1) != 1 {
+		t.Errorf("Expected a depth of 1 after 12")
+	}
+}
@@ -0,0 +1,123 @@
+package st
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestSpanMap(t *testing.T) {
+	for _, coalesce := range []bool{false, true} {
+		var u = NewSpanUtilWithPrev(testDriver.Cmp, testDriver.Next, func(e int) int { return e - 1 })
+		var equal func(a, b int) bool
+		if coalesce {
+			equal = func(a, b int) bool { return a == b }
+		}
+		var r = rand.New(rand.NewSource(23))
+		var m = NewSpanMap(u, equal)
+		// 0 is used for points that are not in the map
+		var values = pointOracle{}
+		runSteps(t, fmt.Sprintf("coalesce: %v", coalesce), 1000, func(i int) error {
+			var span = randomSpan(r, u, 100, 12, false)
+			var value = 0
+			var err error
+			if r.Intn(4) == 0 {
+				err = m.Delete(span)
+			} else {
+				value = 1 + r.Intn(3)
+				err = m.Set(span, value)
+			}
+			if err != nil {
+				return err
+			}
+			values.set(u, span, value)
+
+			var last SpanBoundry[int]
+			var lastValue = 0
+			for span, value := range m.All() {
+				if last != nil {
+					var next, _ = u.after(u.endOf(last))
+					if diff := u.cmpBoundry(next, u.beginOf(span)); diff > 0 || coalesce && diff == 0 && value == lastValue {
+						return fmt.Errorf("ranges are not disjoint: %v, %v", last, span)
+					}
+				}
+				last, lastValue = span, value
+			}
+			if err := values.check("Get", func(p int) int {
+				var value, ok = m.Get(p)
+				if ok == (value == 0) {
+					// only points in the map are found
+					return -1
+				}
+				return value
+			}); err != nil {
+				return err
+			}
+
+			var check = randomSpan(r, u, 100, 10, false)
+			var expected, seen = pointOracle{}, pointOracle{}
+			for p := range expected {
+				if u.Contains(check, p) {
+					expected[p] = values[p]
+				}
+			}
+			for span, value := range m.Range(check) {
+				if begin, end := u.ContainedBy(span, check); begin < 0 || end > 0 {
+					return fmt.Errorf("%v is not clipped to %v", span, check)
+				}
+				seen.set(u, span, value)
+			}
+			if seen != expected {
+				return fmt.Errorf("Range(%v) Expected: %v, got: %v", check, expected, seen)
+			}
+			return nil
+		})
+	}
+}
+
+func TestSpanMapCoalesce(t *testing.T) {
+	var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	var m = NewSpanMap(u, func(a, b string) bool { return a == b })
+	m.Set(u.HalfOpen(1, 100), "a")
+	m.Set(u.HalfOpen(10, 20), "b")
+	m.Set(u.HalfOpen(30, 40), "a")
+	type item struct {
+		begin, end int
+		value      string
+	}
+	var res = []item{}
+	for span, value := range m.All() {
+		res = append(res, item{span.GetBegin(), span.GetEnd(), value})
+	}
+	var expected = []item{{1, 10, "a"}, {10, 20, "b"}, {20, 100, "a"}}
+	if !slices.Equal(res, expected) {
+		t.Errorf("Expected: %v, got: %v", expected, res)
+	}
+
+	m.Set(u.HalfOpen(10, 20), "a")
+	if m.Len() != 1 {
+		t.Errorf("Expected a single range, got: %d", m.Len())
+	}
+	res = res[:0]
+	for span, value := range m.Range(u.HalfOpen(50, 200)) {
+		res = append(res, item{span.GetBegin(), span.GetEnd(), value})
+	}
+	if expected = []item{{50, 100, "a"}}; !slices.Equal(res, expected) {
+		t.Errorf("Expected: %v, got: %v", expected, res)
+	}
+
+	if err := m.Set(u.Ns(5, 1), "c"); !errors.Is(err, ErrBeginAfterEnd) || m.Len() != 1 {
+		t.Errorf("Expected ErrBeginAfterEnd, got: %v", err)
+	}
+	if err := m.Delete(u.Ns(40, 50)); !errors.Is(err, ErrPrevRequired) {
+		t.Errorf("Expected ErrPrevRequired, got: %v", err)
+	}
+	if value, ok := m.Get(45); !ok || value != "a" {
+		t.Errorf("Expected the map to be unchanged, got: %v %v", value, ok)
+	}
+	if _, ok := m.Get(100); ok {
+		t.Errorf("Expected 100 to not be in the map")
+	}
+}
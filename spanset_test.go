@@ -0,0 +1,88 @@
+package st
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestSpanSet(t *testing.T) {
+	for _, consolidate := range []bool{false, true} {
+		var u = NewSpanUtilWithPrev(testDriver.Cmp, testDriver.Next, func(e int) int { return e - 1 })
+		u.Consolidate = consolidate
+		var r = rand.New(rand.NewSource(21))
+		var set = u.NewSpanSet()
+		// 1 for the points in the set
+		var covered = pointOracle{}
+		runSteps(t, fmt.Sprintf("consolidate: %v", consolidate), 1000, func(i int) error {
+			var span = randomSpan(r, u, 100, 12, false)
+			var add = r.Intn(3) != 0
+			var err error
+			if add {
+				err = set.Add(span)
+				covered.set(u, span, 1)
+			} else {
+				err = set.Remove(span)
+				covered.set(u, span, 0)
+			}
+			if err != nil {
+				return err
+			}
+
+			var spans = *set.Spans()
+			for j := 1; j < len(spans); j++ {
+				var next, _ = u.after(u.endOf(spans[j-1]))
+				if diff := u.cmpBoundry(next, u.beginOf(spans[j])); diff > 0 || consolidate && diff == 0 {
+					return fmt.Errorf("spans are not disjoint: %v", spans)
+				}
+			}
+			if err := covered.check("Contains", func(p int) int {
+				if set.Contains(p) {
+					return 1
+				}
+				return 0
+			}); err != nil {
+				return err
+			}
+			var check = randomSpan(r, u, 100, 10, false)
+			if expected := slices.Min(covered.values(u, check)) == 1; set.Covers(check) != expected {
+				return fmt.Errorf("Covers(%v) Expected: %v, spans: %v", check, expected, spans)
+			}
+			return nil
+		})
+	}
+}
+
+func TestSpanSetColumn(t *testing.T) {
+	var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	u.Consolidate = true
+	var set = u.NewSpanSet()
+	set.Add(u.HalfOpen(5, 9))
+	set.Add(u.HalfOpen(1, 6))
+	set.Add(u.HalfOpen(9, 12))
+	set.Remove(u.HalfOpen(3, 4))
+	var expected = []SpanBoundry[int]{u.HalfOpen(1, 3), u.HalfOpen(4, 12)}
+	if res := slices.Collect(set.All()); len(res) != 2 || u.Compare(res[0], expected[0]) != 0 || u.Compare(res[1], expected[1]) != 0 {
+		t.Errorf("Expected: %v, got: %v", expected, res)
+	}
+
+	var cs = u.NewColumnSets()
+	cs.AddColumn(u.NewCoaFromOlssSeq2(set.NewOlssSeq2()))
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{u.HalfOpen(2, 5)})
+	var segments = [][3]int{{1, 2, 1}, {2, 3, 2}, {3, 4, 1}, {4, 5, 2}, {5, 12, 1}}
+	if res := segmentCounts(cs); !slices.Equal(res, segments) {
+		t.Errorf("Expected: %v, got: %v", segments, res)
+	}
+
+	if err := set.Add(u.Ns(5, 1)); !errors.Is(err, ErrBeginAfterEnd) || set.Len() != 2 {
+		t.Errorf("Expected ErrBeginAfterEnd, got: %v", err)
+	}
+	var noPrev = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	var plain = noPrev.NewSpanSet()
+	plain.Add(noPrev.Ns(1, 10))
+	if err := plain.Remove(noPrev.Ns(4, 5)); !errors.Is(err, ErrPrevRequired) || !plain.Covers(noPrev.Ns(1, 10)) {
+		t.Errorf("Expected ErrPrevRequired, got: %v", err)
+	}
+}
@@ -31,6 +31,25 @@
 //    func(e int) int { return e + 1 },
 //  )
 // 
+// Operations that need to carve spans, such as Subtract and Gaps, also require a "Prev" function.
+// The st.NewSpanUtilWithPrev[E any](Cmp,Next,Prev) function takes the "Prev" function as its last argument:
+//
+//  var u = st.NewSpanUtilWithPrev(
+//    cmp.Compare,
+//    func(e int) int { return e + 1 },
+//    func(e int) int { return e - 1 },
+//  )
+//
+// Types with a maximum value, such as int, should use st.NewSpanUtilWithNextOk[E any](Cmp,NextOk,PrevOk), where
+// the bool value returned by "NextOk" and "PrevOk" is false when there is no next or previous value.
+// Iteration then ends cleanly at the maximum value, instead of wrapping around:
+//
+//  var u = st.NewSpanUtilWithNextOk(
+//    cmp.Compare,
+//    func(e int) (int, bool) { return e + 1, e < math.MaxInt },
+//    func(e int) (int, bool) { return e - 1, e > math.MinInt },
+//  )
+//
 // The algorithm is primarily implemented by 2 methods of the SpanUtil[E any] struct:
 //  - FirstSpan, finds the initial data span intersection.
 //  - NextSpan, finds all subsequent data span intersections.
@@ -77,6 +96,42 @@
 //    span, ok = u.NextSpan(span, list)
 //  }
 // 
+// # Open and half open spans
+//
+// By default both the begin and end values of a span are inclusive.  Spans that implement the
+// BoundedSpanBoundry[E] interface can exclude either end, the st.BoundedSpan[E] struct is the
+// default implementation.  Half open spans do not require a Next function, making them the
+// natural model for continuous types such as time.Time:
+//
+//  var u = st.NewSpanUtil(func(a, b time.Time) int { return a.Compare(b) }, nil)
+//  // [start, end)
+//  var span = u.HalfOpen(start, end)
+//
+// # Unbounded spans
+//
+// Spans that have no begin or no end are expressed with sentinel values set by SetInfinity.  The Cmp
+// function is wrapped so the sentinels always sort before or after every other value, and the Next function
+// is never called on the +infinity sentinel:
+//
+//  u.SetInfinity(math.MinInt, math.MaxInt)
+//  // valid from 5 onward
+//  var span = u.NsFrom(5)
+//
+// # Spans with payloads
+//
+// Any SpanBoundry can carry a payload by wrapping it with NewTaggedSpan.  The original spans, and their payloads,
+// are returned by GetSources, and the Tagger function attaches merged payloads to the spans created by the
+// ColumnSets iterator:
+//
+//  u.Tagger = st.MergeTags[int](func(a, b float64) float64 { return a + b })
+//  span := st.NewTaggedSpan(u.Ns(1, 5), 9.99)
+//  // later on
+//  total, ok := st.ValueOf[int, float64](res.GetSpan())
+//
+// When overlapping spans are merged by a SpanOverlapAccumulator, the Merge hook combines their payloads:
+//
+//  u.Merge = st.MergeValues[int](func(a, b float64) float64 { return max(a, b) })
+//
 // # Beyond the basics
 // 
 // Finding overlaps between lists of lists takes a bit more work, but is greatly simplified by this package.
@@ -164,6 +219,160 @@
 //  }
 //  fmt.Print(header)
 //
+// Errors reported by the st package are *st.SpanError[E] values, that carry the offending span, the span before it,
+// the source position and the ColumnId.  Use errors.Is to test for sentinel errors such as st.ErrOutOfSequence:
+//
+//  var spanErr *st.SpanError[int]
+//  if errors.As(ac.Err, &spanErr) && errors.Is(spanErr, st.ErrOutOfSequence) {
+//    fmt.Printf("Column: %s, Row: %d\n", m[spanErr.ColumnId], spanErr.Pos)
+//  }
+//
+// By default the first error halts iteration.  To drop invalid spans and keep going, set the ErrorPolicy to
+// st.SkipOnError, every error is then reported to OnError instead.  A column whose error is not a *st.SpanError,
+// such as a canceled context, is closed:
+//
+//  ac.ErrorPolicy = st.SkipOnError
+//  ac.OnError = func(err *st.SpanError[int]) {
+//    fmt.Printf("Skipped: Column: %s, Row: %d, error was: %v\n", m[err.ColumnId], err.Pos, err)
+//  }
+//
+// # Named columns
+//
+// Instead of keeping a map of ColumnId to names, columns can be added with a name and metadata.  Both are exposed on
+// each CurrentColumn, the name can be looked up with ColumnIdOf, and it is included in errors as ColumnName:
+//
+//  ac.AddNamedColumnFromSpanSlice("SetA", nil, &[]st.SpanBoundry[int]{u.Ns(1, 2)})
+//  for _, col := range *res.GetColumns() {
+//    fmt.Printf("%s:(%d-%d)", col.Name, col.GetSrcId(), col.GetEndId())
+//  }
+//
+// # Adding and removing columns during iteration
+//
+// Columns can be added while Iter is running, they join from the current segment onward and are included starting
+// with the next segment.  RemoveColumn closes a column, which runs the functions registered with its AddOnClose
+// method, and it is no longer included in any segment:
+//
+//  for _, res := range ac.Iter() {
+//    if feedOpened {
+//      ac.AddColumnFromNewOlssChanStater(s)
+//    }
+//    if feedDone {
+//      ac.RemoveColumn(id)
+//    }
+//  }
+//
+// # Parallel execution
+//
+// For data sets with many columns, ShardedColumnSets divides E into disjoint ranges, runs a ColumnSets instance for each
+// range on a pool of go routines, and stitches the results back together in order.  The split points can be given,
+// or sampled from the data.  Each Segment is a copy, so it is safe to keep:
+//
+//  sc := u.NewShardedColumnSets()
+//  sc.AddColumnFromSpanSlice(&[]st.SpanBoundry[int]{u.Ns(1, 2)})
+//  sc.SampleSplits(8)
+//  for _, seg := range sc.Iter() {
+//    fmt.Printf("%v -> %v: %d\n", seg.GetBegin(), seg.GetEnd(), seg.OverlapCount())
+//  }
+//
+// # Heap engine
+//
+// By default each step of ColumnSets.Iter scans every column.  When there are many columns, and only a few of them
+// overlap at a time, the HeapEngine keeps the columns in a min-heap ordered by their next begin value, so each step
+// only visits the columns that overlap with the segment.  The segments produced are the same:
+//
+//  ac.Engine = st.HeapEngine
+//
+// # Reusing buffers
+//
+// By default every segment produced by ColumnSets.Iter allocates a new span, a new list of columns and a new
+// CurrentColumn for each column.  Setting ReuseBuffers reuses them instead, so no memory is allocated per segment,
+// but the values are then only valid until the loop body returns:
+//
+//  ac.ReuseBuffers = true
+//  for _, res := range ac.Iter() {
+//    // copy what needs to be kept
+//    keep = append(keep, u.Ns(res.GetBegin(), res.GetEnd()))
+//  }
+//
+// # Join modes
+//
+// By default every segment is produced, including the gaps between columns where OverlapCount is 0.  Setting the
+// JoinMode limits the segments to: JoinAny, JoinInner (all open columns), JoinLeft (column 0), JoinAnti (only column 0)
+// or JoinAtLeast (MinColumns or more):
+//
+//  ac.JoinMode = st.JoinAtLeast
+//  ac.MinColumns = 2
+//
+// # Reducing segments
+//
+// Instead of writing the same loops over GetColumns, each segment can be reduced to a single value with st.Reduce.
+// Adjacent segments that reduce to the same value are merged.  The built in reducers are: CountColumns, SumValues,
+// MinValue, MaxValue and HighestPriority:
+//
+//  for span, total := range st.Reduce(ac, st.SumValues[int, float64]()) {
+//    fmt.Printf("%v -> %v: %v\n", span.GetBegin(), span.GetEnd(), total)
+//  }
+//
+// # Interval trees
+//
+// IntervalTree is a persistent index of spans, for finding the spans that contain a point, or overlap with a span.
+// Insert and Delete return a new tree, and leave the original unchanged:
+//
+//  tree, _ := u.NewIntervalTreeFromSortedSlice(&[]st.SpanBoundry[int]{u.Ns(1, 5), u.Ns(3, 9)})
+//  for span := range tree.Stab(4) {
+//    fmt.Println(span)
+//  }
+//
+// # Span sets
+//
+// SpanSet keeps a sorted list of disjoint spans, that can be added and removed in any order.  Overlapping spans
+// are merged, and when Consolidate is true adjacent spans are merged too:
+//
+//  set := u.NewSpanSet()
+//  set.Add(u.Ns(1, 6))
+//  set.Remove(u.Ns(3, 3))
+//  fmt.Println(set.Contains(3), set.Covers(u.Ns(4, 6)))
+//
+// # Counting coverage
+//
+// SpanCounter counts how many spans cover each value.  Add and Remove change the depth of the values in a span, and
+// Segments iterates the parts of the step function where the depth does not change:
+//
+//  counter := u.NewSpanCounter()
+//  counter.Add(u.Ns(1, 10))
+//  counter.Add(u.Ns(5, 20))
+//  fmt.Println(counter.Depth(7), counter.MaxDepth(u.Ns(1, 4)))
+//
+// # Range maps
+//
+// SpanMap assigns values to ranges.  Set overwrites the values of the ranges it overlaps with, and when an equality
+// function is given, adjacent ranges with equal values are coalesced:
+//
+//  rates := st.NewSpanMap(u, func(a, b float64) bool { return a == b })
+//  rates.Set(u.HalfOpen(1, 100), 0.5)
+//  rates.Set(u.HalfOpen(10, 20), 0.25)
+//  rate, ok := rates.Get(15)
+//
+// # Cancellation
+//
+// IterContext stops the iterator once a context is done, and sets Err to the reason.  The columns added by
+// AddColumnFromNewOlssChanStater are canceled as well, so their go routines stop pushing:
+//
+//  ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+//  defer cancel()
+//  for _, seg := range ac.IterContext(ctx) {
+//    fmt.Println(seg.GetSpan())
+//  }
+//  if errors.Is(ac.Err, context.DeadlineExceeded) {
+//    fmt.Println("timed out")
+//  }
+//
+// NewOlssChanStaterContext derives the context of a channel based column from a parent context, so a request scoped
+// cancellation reaches the producer go routine.  The channel is closed and OlssChanStater.Err is set to the cause,
+// which tells a cancellation apart from the end of the data:
+//
+//  s := u.NewSpanOverlapAccumulator().NewOlssChanStaterContext(ctx)
+//
 // # Integrating go routines and streaming data sets
 //
 // The internals of the st package, can be used to create context instances to mange communication between go routines for us.
@@ -217,6 +426,16 @@
 // The for loop and map remain unchanged from our previous example.  The only differnce is the internals have no way 
 // to sort the data before it is consolidated.
 //
+// When the data is only slightly out of order, a reorder buffer can be turned on.  Spans are held back and sorted,
+// either until more than ReorderWindow spans are buffered, or until they are older than the MaxLateness allows.
+// Setting RepairInverted swaps the begin and end values of inverted spans:
+//
+//  sa := u.NewSpanOverlapAccumulator()
+//  sa.ReorderWindow = 16
+//  sa.MaxLateness = st.Lateness(10)
+//  sa.RepairInverted = true
+//  s := sa.NewOlssChanStater()
+//
 // [Project]: https://github.com/akalinux/span-tools
 // [cmp.Compare]: https://pkg.go.dev/cmp#Compare
 package st
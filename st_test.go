@@ -2,6 +2,7 @@ package st
 
 import (
 	"cmp"
+	"errors"
 	"slices"
 	"testing"
 )
@@ -92,6 +93,9 @@ func TestNewSpan(t *testing.T) {
 	if err == nil {
 		t.Errorf("Should have an error here")
 	}
+	if !errors.Is(err, ErrBeginAfterEnd) {
+		t.Errorf("Expected ErrBeginAfterEnd, got: %v", err)
+	}
 }
 
 // Validates sort operation, by sorting slices and compairing the the sorted elements to a manually sorted array.
@@ -349,3 +353,14 @@ func TestChanAccumulatro(t *testing.T) {
 	}
 
 }
+
+func TestCheckPrev(t *testing.T) {
+	var u = NewSpanUtilWithPrev(testDriver.Cmp, testDriver.Next, SubOne)
+	if err := u.Check(u.Ns(1, 2), nil); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	u.Prev = func(e int) int { return e - 2 }
+	if err := u.Check(u.Ns(1, 2), nil); err == nil {
+		t.Error("Expected an error when Prev(Next(x)) is not equal to x")
+	}
+}
@@ -0,0 +1,103 @@
+package st
+
+import (
+	"testing"
+)
+
+func TestTaggedSpan(t *testing.T) {
+	var span = NewTaggedSpan(testDriver.Nb(1, true, 3, false), "a")
+	if span.GetBegin() != 1 || span.GetEnd() != 3 || !spanBeginInclusive[int](span) || spanEndInclusive[int](span) {
+		t.Errorf("Invalid span: %v", span)
+	}
+	if value, ok := ValueOf[int, string](span); !ok || value != "a" {
+		t.Errorf("Expected a, got: %v", value)
+	}
+	if _, ok := ValueOf[int, int](span); ok {
+		t.Errorf("Should not find an int payload")
+	}
+	if _, ok := ValueOf[int, string](testDriver.Ns(1, 2)); ok {
+		t.Errorf("Should not find a payload on a plain span")
+	}
+}
+
+func TestTaggedSources(t *testing.T) {
+	var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	var ac = u.NewSpanOverlapAccumulator()
+	var list = &[]SpanBoundry[int]{
+		NewTaggedSpan(u.Ns(1, 3), "a"),
+		NewTaggedSpan(u.Ns(2, 4), "b"),
+		NewTaggedSpan(u.Ns(7, 8), "c"),
+	}
+	var res = [][]string{}
+	for _, ol := range ac.NewOlssSeq2FromSbSlice(list) {
+		res = append(res, *Values[int, string](ol.GetSources()))
+	}
+	if len(res) != 2 || len(res[0]) != 2 || res[0][0] != "a" || res[0][1] != "b" || len(res[1]) != 1 || res[1][0] != "c" {
+		t.Errorf("Invalid payloads: %v", res)
+	}
+}
+
+func TestTaggedColumnSets(t *testing.T) {
+	var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	u.Tagger = MergeTags[int](func(a, b int) int { return a + b })
+	var cs = u.NewColumnSets()
+	defer cs.Close()
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{NewTaggedSpan(u.Ns(1, 5), 10)})
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{NewTaggedSpan(u.Ns(3, 7), 1)})
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{u.Ns(2, 9)})
+	var count = 0
+	for _, res := range cs.Iter() {
+		var expected = 0
+		for _, col := range *res.GetColumns() {
+			for _, value := range *Values[int, int](col.GetSources()) {
+				expected += value
+			}
+		}
+		var value, ok = ValueOf[int, int](res.GetSpan())
+		if expected == 0 && ok {
+			t.Errorf("Span: %v, should not have a payload", res.GetSpan())
+		} else if value != expected {
+			t.Errorf("Span: %v, expected payload: %d, got: %d", res.GetSpan(), expected, value)
+		}
+		count++
+	}
+	if count == 0 {
+		t.Errorf("Expected results")
+	}
+
+	var overlap, _ = u.CreateOverlapSpan(&[]SpanBoundry[int]{NewTaggedSpan(u.Ns(1, 5), 2), NewTaggedSpan(u.Ns(3, 7), 3)})
+	if value, _ := ValueOf[int, int](overlap); value != 5 || overlap.GetBegin() != 3 || overlap.GetEnd() != 5 {
+		t.Errorf("Invalid overlap: %v, payload: %d", overlap, value)
+	}
+}
+
+func TestMergeValues(t *testing.T) {
+	var u = NewSpanUtil(testDriver.Cmp, testDriver.Next)
+	u.Consolidate = true
+	u.Merge = MergeValues[int](func(a, b int) int { return a + b })
+	var ac = u.NewSpanOverlapAccumulator()
+	var list = &[]SpanBoundry[int]{
+		NewTaggedSpan(u.Ns(1, 3), 1),
+		NewTaggedSpan(u.Ns(2, 2), 2),
+		u.Ns(3, 5),
+		NewTaggedSpan(u.Ns(6, 7), 4),
+		NewTaggedSpan(u.Ns(10, 11), 8),
+	}
+	var res = [][3]int{}
+	for _, ol := range ac.NewOlssSeq2FromSbSlice(list) {
+		var value, _ = ValueOf[int, int](ol.GetSpan())
+		res = append(res, [3]int{ol.GetBegin(), ol.GetEnd(), value})
+	}
+	if len(res) != 2 || res[0] != [3]int{1, 7, 7} || res[1] != [3]int{10, 11, 8} {
+		t.Errorf("Invalid merged results: %v", res)
+	}
+
+	// the default comes from SpanUtil, but can be changed per accumulator
+	ac = u.NewSpanOverlapAccumulator()
+	ac.Merge = MergeValues[int](func(a, b int) int { return max(a, b) })
+	for _, ol := range ac.NewOlssSeq2FromSbSlice(list) {
+		if value, _ := ValueOf[int, int](ol.GetSpan()); ol.GetBegin() == 1 && value != 4 {
+			t.Errorf("Expected the max value of 4, got: %d", value)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package st
+
+import (
+	"math"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestIntSpanUtilOverflow(t *testing.T) {
+	var u = NewIntSpanUtil[int8]()
+	if u.Next(math.MaxInt8) != math.MaxInt8 || u.Prev(math.MinInt8) != math.MinInt8 {
+		t.Error("Next and Prev should not overflow")
+	}
+	var cs = u.NewColumnSets()
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int8]{u.Ns(120, math.MaxInt8)})
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int8]{u.Ns(100, 125)})
+	var count = 0
+	for range cs.Iter() {
+		count++
+		if count > 10 {
+			t.Error("Iteration did not stop at the max value")
+			return
+		}
+	}
+	if cs.Err != nil {
+		t.Errorf("Unexpected error: %v", cs.Err)
+	}
+	var res, err = u.Subtract(&[]SpanBoundry[int8]{u.Ns(math.MinInt8, math.MaxInt8)}, &[]SpanBoundry[int8]{u.Ns(0, 0)})
+	if err != nil || len(*res) != 2 || (*res)[0].GetEnd() != -1 || (*res)[1].GetBegin() != 1 {
+		t.Errorf("Invalid Subtract: %v, %v", res, err)
+	}
+
+	var uu = NewIntSpanUtil[uint8]()
+	if uu.Next(math.MaxUint8) != math.MaxUint8 || uu.Prev(0) != 0 || uu.Next(1) != 2 {
+		t.Error("Next and Prev should not overflow")
+	}
+}
+
+func TestAddrSpanUtil(t *testing.T) {
+	var u = NewAddrSpanUtil()
+	var max = netip.MustParseAddr("255.255.255.255")
+	if u.Next(max) != max {
+		t.Error("Next should not overflow")
+	}
+	var zero = netip.MustParseAddr("0.0.0.0")
+	if u.Prev(zero) != zero {
+		t.Error("Prev should not underflow")
+	}
+	var gaps = []SpanBoundry[netip.Addr]{}
+	for gap, err := range u.GapsFromSlice(
+		u.Ns(netip.MustParseAddr("10.0.0.0"), netip.MustParseAddr("10.0.0.255")),
+		&[]SpanBoundry[netip.Addr]{u.Ns(netip.MustParseAddr("10.0.0.10"), netip.MustParseAddr("10.0.0.255"))},
+	) {
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			return
+		}
+		gaps = append(gaps, gap)
+	}
+	if len(gaps) != 1 || gaps[0].GetEnd() != netip.MustParseAddr("10.0.0.9") {
+		t.Errorf("Invalid gaps: %v", gaps)
+	}
+	var ac = u.NewSpanOverlapAccumulator()
+	ac.Consolidate = true
+	ac.Accumulate(u.Ns(netip.MustParseAddr("255.255.255.0"), max))
+	if _, err := ac.Accumulate(u.Ns(max, max)); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestFloatSpanUtil(t *testing.T) {
+	var u = NewFloatSpanUtil()
+	if u.Next(1) <= 1 || u.Prev(u.Next(1)) != 1 {
+		t.Error("Invalid Next or Prev")
+	}
+	if u.Next(math.Inf(1)) != math.Inf(1) {
+		t.Error("Next should stop at +Inf")
+	}
+	var span, ok = u.NextSpan(u.Ns(0, math.Inf(1)), &[]SpanBoundry[float64]{u.Ns(0, math.Inf(1))})
+	if ok {
+		t.Errorf("There should be no span after +Inf, got: %v", span)
+	}
+}
+
+func TestTimeSpanUtils(t *testing.T) {
+	var now = time.Date(2024, time.February, 28, 13, 30, 0, 0, time.UTC)
+	var u = NewTimeSpanUtil(time.Second)
+	if !u.Next(now).Equal(now.Add(time.Second)) || !u.Prev(now).Equal(now.Add(-time.Second)) {
+		t.Error("Invalid Next or Prev")
+	}
+	if NewTimeSpanUtil(0).Next != nil {
+		t.Error("Next should be nil")
+	}
+
+	var d = NewDateSpanUtil()
+	var next = d.Next(now)
+	if !next.Equal(time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Invalid next date: %v", next)
+	}
+	if d.Cmp(now, time.Date(2024, time.February, 28, 0, 0, 0, 0, time.UTC)) != 0 {
+		t.Error("Dates should ignore the time of day")
+	}
+	if d.Cmp(now, next) != -1 || d.Cmp(next, now) != 1 || d.Cmp(now, now.AddDate(1, 0, 0)) != -1 || d.Cmp(now, now.AddDate(0, 1, 0)) != -1 {
+		t.Error("Invalid date compare")
+	}
+	if d.Check(d.Ns(now, next), nil) != nil {
+		t.Error("Expected Check to pass")
+	}
+}
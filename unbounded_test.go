@@ -0,0 +1,82 @@
+package st
+
+import (
+	"cmp"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestUnboundedCmp(t *testing.T) {
+	var u = NewSpanUtil(strings.Compare, func(e string) string { return e + "\x00" })
+	if _, ok := u.PosInf(); ok {
+		t.Error("Should not have infinity before SetInfinity")
+	}
+	u.SetInfinity("-inf", "+inf")
+	u.SetInfinity("<", ">")
+	if u.Cmp(">", "zzz") != 1 || u.Cmp("<", "") != -1 || u.Cmp(">", ">") != 0 || u.Cmp("a", "b") != -1 {
+		t.Error("Invalid sentinel aware Cmp")
+	}
+	if u.Cmp("+inf", "a") != -1 {
+		t.Error("Old sentinels should no longer be infinite")
+	}
+	if pos, _ := u.PosInf(); pos != ">" {
+		t.Errorf("Expected >, got: %s", pos)
+	}
+	if neg, _ := u.NegInf(); neg != "<" {
+		t.Errorf("Expected <, got: %s", neg)
+	}
+	if !u.Contains(u.NsFrom("m"), "zzz") || u.Contains(u.NsFrom("m"), "a") || !u.Contains(u.NsUntil("m"), "") {
+		t.Error("Invalid Contains for unbounded spans")
+	}
+	if !u.Overlap(u.NsAll(), u.Ns("a", "b")) {
+		t.Error("Everything should overlap with NsAll")
+	}
+}
+
+func TestUnboundedColumnSets(t *testing.T) {
+	var u = NewSpanUtil(cmp.Compare, AddOne)
+	u.SetInfinity(math.MinInt, math.MaxInt)
+	var cs = u.NewColumnSets()
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{u.NsFrom(5)})
+	cs.AddColumnFromSpanSlice(&[]SpanBoundry[int]{u.NsUntil(1), u.Ns(3, 7)})
+	var last SpanBoundry[int]
+	var count = 0
+	for _, res := range cs.Iter() {
+		count++
+		last = res.GetSpan()
+		if count > 10 {
+			t.Error("Iteration did not stop at +infinity")
+			return
+		}
+	}
+	if cs.Err != nil {
+		t.Errorf("Unexpected error: %v", cs.Err)
+	}
+	if last == nil || !u.IsPosInf(last.GetEnd()) {
+		t.Errorf("Expected the last span to end at +infinity, got: %v", last)
+	}
+}
+
+func TestUnboundedAccumulate(t *testing.T) {
+	var u = NewSpanUtil(cmp.Compare, func(e int) int {
+		if e == math.MaxInt {
+			panic("Next called on +infinity")
+		}
+		return e + 1
+	})
+	u.SetInfinity(math.MinInt, math.MaxInt)
+	u.Consolidate = true
+	u.Prev = SubOne
+	var ac = u.NewSpanOverlapAccumulator()
+	var first, _ = ac.Accumulate(u.NsUntil(1))
+	var next, _ = ac.Accumulate(u.Ns(2, 3))
+	if first != next || !u.IsNegInf(next.GetBegin()) || next.GetEnd() != 3 {
+		t.Errorf("Expected -inf->3, got: %v", next.Span)
+	}
+	ac.Accumulate(u.NsFrom(3))
+	var res, err = u.Subtract(&[]SpanBoundry[int]{u.NsAll()}, &[]SpanBoundry[int]{u.NsFrom(10)})
+	if err != nil || len(*res) != 1 || !u.IsNegInf((*res)[0].GetBegin()) || (*res)[0].GetEnd() != 9 {
+		t.Errorf("Expected -inf->9, got: %v, %v", res, err)
+	}
+}